@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"ghreporting/internal/client"
+	"ghreporting/internal/daemon"
+	"ghreporting/internal/models"
+	"ghreporting/internal/reporter"
+)
+
+// runDaemon runs the report jobs of a configuration file on their cron schedules
+func runDaemon(args []string) {
+	fs := flag.NewFlagSet("daemon", flag.ExitOnError)
+	var (
+		configFile    = fs.String("config", "", "Daemon configuration file listing the scheduled jobs (required)")
+		token         = fs.String("token", "", "GitHub token (optional, can use GITHUB_TOKEN env var)")
+		once          = fs.Bool("once", false, "Run every job immediately once and exit instead of waiting for the schedules")
+		showHistory   = fs.Bool("history", false, "Print the run history and exit")
+		repoWorkers   = fs.Int("repo-workers", 10, "Number of repositories processed at once (their API requests are capped by -max-requests)")
+		branchWorkers = fs.Int("branch-workers", 1, "Number of branches of each repository fetched at once (their API requests are capped by -max-requests)")
+		commitWorkers = fs.Int("commit-workers", 1, "Number of commit details fetched at once for each branch (their API requests are capped by -max-requests)")
+		maxRequests   = fs.Int("max-requests", 10, "Number of GitHub API requests in flight at once across all workers; raise it with the worker counts")
+		adaptive      = fs.Bool("adaptive-concurrency", false, "Reduce API requests in flight as the remaining API rate limit shrinks")
+		githubTeams   = fs.Bool("github-teams", false, "Aggregate contributors per GitHub team of the organization (requires the read:org scope)")
+		flags         = addReportFlags(fs)
+		deliveries    = addDeliveryFlags(fs)
+		logs          = addLogFlags(fs)
+	)
+	logs.parse(fs, args)
+
+	if *configFile == "" {
+		fmt.Fprintf(os.Stderr, "Error: -config parameter is required\n")
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	rep := reporter.NewReporter(nil)
+	if err := flags.configure(rep); err != nil {
+		fatal("Invalid option", err)
+	}
+	if err := deliveries.validate(); err != nil {
+		fatal("Invalid option", err)
+	}
+
+	config, err := daemon.LoadConfig(*configFile, rep)
+	if err != nil {
+		fatal("Invalid daemon configuration", err)
+	}
+
+	if *showHistory {
+		printHistory(config.History)
+		return
+	}
+
+	ghToken := *token
+	if ghToken == "" {
+		ghToken = os.Getenv("GITHUB_TOKEN")
+	}
+	ghClient := client.NewGitHubClient(ghToken)
+	ghClient.SetCommitConcurrency(*commitWorkers, *adaptive)
+	ghClient.SetMaxRequests(*maxRequests)
+
+	// Each run gets its own reporter so jobs with different branch options can overlap
+	generate := func(ctx context.Context, job daemon.Job, since, until time.Time) (*models.Report, error) {
+		jobRep := reporter.NewReporter(ghClient)
+		jobRep.SetAllBranches(job.AllBranches)
+		jobRep.SetResolvePullRequests(job.ResolvePRs)
+		jobRep.SetGitHubTeams(*githubTeams)
+		jobRep.SetConcurrency(*repoWorkers, *branchWorkers)
+		if err := flags.configure(jobRep); err != nil {
+			return nil, err
+		}
+		return jobRep.GenerateReport(ctx, job.Target, since, until)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	d := daemon.New(config, rep, generate)
+	d.SetDelivery(func(ctx context.Context, report *models.Report) error {
+		return deliveries.deliver(ctx, rep, report)
+	})
+	if *once {
+		status := 0
+		for _, job := range config.Jobs {
+			if run := d.RunJob(ctx, job); run.Status != daemon.StatusSuccess {
+				status = 1
+			}
+		}
+		os.Exit(status)
+	}
+
+	if err := d.Run(ctx); err != nil {
+		fatal("Daemon failed", err)
+	}
+}
+
+// printHistory lists the recorded runs, oldest first
+func printHistory(path string) {
+	runs, err := daemon.LoadHistory(path)
+	if err != nil {
+		fatal("Failed to load run history", err)
+	}
+
+	for _, run := range runs {
+		fmt.Printf("%s  %-20s %-10s %8s  repos=%d contributors=%d diagnostics=%d",
+			run.Started.Format(time.RFC3339), run.Job, run.Status, run.Finished.Sub(run.Started).Round(time.Second),
+			run.Repositories, run.Contributors, run.Diagnostics)
+		if run.Error != "" {
+			fmt.Printf("  error=%q", run.Error)
+		}
+		fmt.Println()
+	}
+}
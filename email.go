@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"ghreporting/internal/delivery"
+	"ghreporting/internal/models"
+	"ghreporting/internal/reporter"
+)
+
+// File extensions of email attachments, by format; other formats use their name
+var attachmentExtensions = map[string]string{
+	"text":           "txt",
+	"markdown":       "md",
+	"template":       "txt",
+	"commits-csv":    "commits.csv",
+	"commits-ndjson": "commits.ndjson",
+	"openmetrics":    "prom",
+	"prometheus":     "prom",
+	"slack":          "slack.json",
+}
+
+// emails returns the email delivery configuration selected by -email-config, or nil
+func (f *deliveryFlags) emails() (*delivery.EmailConfig, error) {
+	if *f.emailConfig == "" {
+		return nil, nil
+	}
+	config, err := delivery.LoadEmailConfig(*f.emailConfig)
+	if err != nil {
+		return nil, err
+	}
+	for _, format := range config.Attachments {
+		if _, ok := reporter.ContentType(format); !ok {
+			return nil, fmt.Errorf("unsupported attachment format: %s", format)
+		}
+	}
+	return config, nil
+}
+
+// sendEmails emails the report to every recipient. Recipients with repository or
+// contributor filters receive only their part of the report.
+func (f *deliveryFlags) sendEmails(ctx context.Context, rep *reporter.Reporter, report *models.Report) error {
+	config, err := f.emails()
+	if err != nil || config == nil {
+		return err
+	}
+
+	var errs []error
+	for _, recipient := range config.Recipients {
+		email, err := reportEmail(rep, report, config, recipient)
+		if err == nil {
+			err = config.SMTP.Send(ctx, email)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("email to %s: %w", strings.Join(recipient.To, ", "), err))
+			continue
+		}
+		slog.Info("Emailed report", "recipients", len(recipient.To), "name", recipient.Name)
+	}
+	return errors.Join(errs...)
+}
+
+// reportEmail renders the recipient's part of the report as an email
+func reportEmail(rep *reporter.Reporter, report *models.Report, config *delivery.EmailConfig, recipient delivery.Recipient) (*delivery.Email, error) {
+	if len(recipient.Repositories) > 0 || len(recipient.Contributors) > 0 {
+		var err error
+		report, err = rep.FilterReport(report, reporter.ReportFilter{
+			Repositories: recipient.Repositories,
+			Contributors: recipient.Contributors,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	render := func(format string) ([]byte, error) {
+		var buf bytes.Buffer
+		if err := rep.WriteReport(&buf, report, format); err != nil {
+			return nil, fmt.Errorf("failed to render %s: %w", format, err)
+		}
+		return buf.Bytes(), nil
+	}
+
+	html, err := render("html")
+	if err != nil {
+		return nil, err
+	}
+	text, err := render("text")
+	if err != nil {
+		return nil, err
+	}
+
+	since, until := report.Period.Since.Format("2006-01-02"), report.Period.Until.Format("2006-01-02")
+	email := &delivery.Email{
+		From: config.From,
+		To:   recipient.To,
+		Subject: strings.NewReplacer(
+			"{target}", report.Target,
+			"{since}", since,
+			"{until}", until,
+			"{name}", recipient.Name,
+		).Replace(config.Subject),
+		HTML: string(html),
+		Text: string(text),
+	}
+
+	for _, format := range config.Attachments {
+		data, err := render(format)
+		if err != nil {
+			return nil, err
+		}
+		extension, ok := attachmentExtensions[format]
+		if !ok {
+			extension = format
+		}
+		contentType, _ := reporter.ContentType(format)
+		email.Attachments = append(email.Attachments, delivery.Attachment{
+			Filename:    fmt.Sprintf("%s-%s.%s", report.Target, until, extension),
+			ContentType: contentType,
+			Data:        data,
+		})
+	}
+	return email, nil
+}
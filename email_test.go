@@ -0,0 +1,95 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"ghreporting/internal/delivery"
+	"ghreporting/internal/models"
+	"ghreporting/internal/reporter"
+)
+
+func testEmailReport(t *testing.T, rep *reporter.Reporter) *models.Report {
+	commit := func(sha, login string, additions int) models.Commit {
+		return models.Commit{
+			SHA:    sha,
+			Author: models.Author{Name: login, Email: login + "@example.com", Login: login},
+			Date:   time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC),
+			Stats:  models.CommitStats{Additions: additions, Total: additions},
+		}
+	}
+	report := &models.Report{
+		Target: "myorg",
+		Period: models.Period{
+			Since: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			Until: time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+		},
+		Repositories: []models.Repository{
+			{Name: "api-core", FullName: "myorg/api-core", Branches: []models.Branch{{Name: "main", Commits: []models.Commit{commit("a1", "alice", 10)}}}},
+			{Name: "web", FullName: "myorg/web", Branches: []models.Branch{{Name: "main", Commits: []models.Commit{commit("b1", "bob", 20)}}}},
+		},
+	}
+
+	// Filtering without restrictions computes the summary
+	report, err := rep.FilterReport(report, reporter.ReportFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return report
+}
+
+func TestReportEmailFiltersPerRecipient(t *testing.T) {
+	rep := reporter.NewReporter(nil)
+	report := testEmailReport(t, rep)
+	config := &delivery.EmailConfig{
+		From:        "reports@example.com",
+		Subject:     "Activity {name}: {since} to {until}",
+		Attachments: []string{"csv"},
+	}
+
+	api, err := reportEmail(rep, report, config, delivery.Recipient{Name: "API", To: []string{"api@example.com"}, Repositories: []string{"myorg/api-*"}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	web, err := reportEmail(rep, report, config, delivery.Recipient{Name: "Web", To: []string{"web@example.com"}, Contributors: []string{"bob@example.com"}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if api.Subject != "Activity API: 2024-01-01 to 2024-01-31" {
+		t.Errorf("Unexpected subject %q", api.Subject)
+	}
+
+	for _, tt := range []struct {
+		name          string
+		email         *delivery.Email
+		want, without string
+	}{
+		{"API", api, "alice", "bob"},
+		{"Web", web, "bob", "alice"},
+	} {
+		if !strings.Contains(tt.email.Text, tt.want) || strings.Contains(tt.email.Text, tt.without) {
+			t.Errorf("%s: expected text body with %s and without %s, got:\n%s", tt.name, tt.want, tt.without, tt.email.Text)
+		}
+		if !strings.Contains(tt.email.HTML, tt.want) || strings.Contains(tt.email.HTML, tt.without) {
+			t.Errorf("%s: expected HTML body with %s and without %s", tt.name, tt.want, tt.without)
+		}
+		if len(tt.email.Attachments) != 1 || tt.email.Attachments[0].Filename != "myorg-2024-01-31.csv" {
+			t.Fatalf("%s: unexpected attachments %+v", tt.name, tt.email.Attachments)
+		}
+		csv := string(tt.email.Attachments[0].Data)
+		if !strings.Contains(csv, tt.want) || strings.Contains(csv, tt.without) {
+			t.Errorf("%s: expected attachment with %s and without %s, got:\n%s", tt.name, tt.want, tt.without, csv)
+		}
+	}
+
+	// Recipients without filters receive the whole report
+	all, err := reportEmail(rep, report, config, delivery.Recipient{To: []string{"cto@example.com"}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(all.Text, "alice") || !strings.Contains(all.Text, "bob") {
+		t.Errorf("Expected the unfiltered report, got:\n%s", all.Text)
+	}
+}
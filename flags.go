@@ -0,0 +1,334 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"ghreporting/internal/delivery"
+	"ghreporting/internal/models"
+	"ghreporting/internal/reporter"
+)
+
+// reportFlags are the options that shape report contents, shared by every command
+// that produces reports
+type reportFlags struct {
+	tmplFile    *string
+	sortOrder   *string
+	noCoAuthors *bool
+	coSplit     *string
+	attributeBy *string
+	periodBy    *string
+	attributePR *bool
+	teamsFile   *string
+}
+
+func addReportFlags(fs *flag.FlagSet) *reportFlags {
+	return &reportFlags{
+		tmplFile:    fs.String("template", "", "Go template file for -format template (*.html templates use html/template)"),
+		sortOrder:   fs.String("sort", "changes", "Contributor order: changes, commits, additions, deletions, name"),
+		noCoAuthors: fs.Bool("no-co-authors", false, "Ignore Co-authored-by trailers when attributing commits"),
+		coSplit:     fs.String("co-author-split", "author", "How co-authored line changes are credited: author, even, full"),
+		attributeBy: fs.String("attribute-by", "author", "Credit commits to their author or committer"),
+		periodBy:    fs.String("period-by", "committer", "Filter commits into the period by author or committer date"),
+		attributePR: fs.Bool("attribute-prs", false, "Credit default branch commits to the author of their pull request (implies -resolve-prs)"),
+		teamsFile:   fs.String("teams-file", "", "JSON file mapping contributors to a team or list of teams, adding a team summary to every output"),
+	}
+}
+
+// outputFlags select where the report of a single run is written and its exit status
+type outputFlags struct {
+	fs         *flag.FlagSet
+	outputFile *string
+	outputList outputList
+	format     *string
+	runID      *string
+	strict     *bool
+}
+
+func addOutputFlags(fs *flag.FlagSet) *outputFlags {
+	f := &outputFlags{
+		fs:         fs,
+		outputFile: fs.String("output", "", "Output file path, or directory for -format parquet (default: stdout)"),
+		format:     fs.String("format", "text", "Output format: text, json, csv, html, markdown, template, sqlite, commits-csv, commits-ndjson, parquet, openmetrics, xlsx, slack"),
+		runID:      fs.String("run-id", "", "Run identifier for -format sqlite (default: current UTC timestamp)"),
+		strict:     fs.Bool("strict", false, "Exit with status 1 when any repository, branch or commit was skipped because of an error"),
+	}
+	fs.Var(&f.outputList, "outputs", "Comma-separated format=path pairs rendering several formats from one run, e.g. text=-,json=report.json (repeatable; replaces -format and -output)")
+	return f
+}
+
+// deliveryFlags configure where written reports are sent
+type deliveryFlags struct {
+	slackWebhook   *string
+	webhook        *string
+	webhookHeaders headerList
+	webhookSecret  *string
+	retries        *int
+	emailConfig    *string
+}
+
+func addDeliveryFlags(fs *flag.FlagSet) *deliveryFlags {
+	f := &deliveryFlags{
+		slackWebhook:  fs.String("slack-webhook", "", "Post a summary with the top contributors to this Slack incoming webhook URL"),
+		webhook:       fs.String("webhook", "", "POST the JSON report to this URL"),
+		webhookSecret: fs.String("webhook-secret", "", "Sign -webhook requests with HMAC-SHA256 using this secret (or GHREPORTING_WEBHOOK_SECRET env var)"),
+		retries:       fs.Int("delivery-retries", 3, "Number of times a failed webhook delivery is retried"),
+		emailConfig:   fs.String("email-config", "", "Email the report to the recipients listed in this SMTP delivery configuration file"),
+	}
+	fs.Var(&f.webhookHeaders, "webhook-header", "Extra -webhook request header as \"Name: value\" (repeatable)")
+	return f
+}
+
+// configure applies the report flags to the reporter
+func (f *reportFlags) configure(rep *reporter.Reporter) error {
+	coAuthorSplit, err := reporter.ParseCoAuthorSplit(*f.coSplit)
+	if err != nil {
+		return fmt.Errorf("invalid co-author split: %w", err)
+	}
+	attribution, err := reporter.ParseIdentitySource(*f.attributeBy)
+	if err != nil {
+		return fmt.Errorf("invalid attribute-by: %w", err)
+	}
+	periodBasis, err := reporter.ParseIdentitySource(*f.periodBy)
+	if err != nil {
+		return fmt.Errorf("invalid period-by: %w", err)
+	}
+	if err := reporter.ValidateSortOrder(*f.sortOrder); err != nil {
+		return err
+	}
+
+	rep.SetCoAuthors(!*f.noCoAuthors)
+	rep.SetCoAuthorSplit(coAuthorSplit)
+	rep.SetAttribution(attribution)
+	rep.SetPeriodBasis(periodBasis)
+	rep.SetPullRequestAttribution(*f.attributePR)
+	rep.SetSortOrder(*f.sortOrder)
+	rep.SetTemplate(*f.tmplFile)
+	if *f.teamsFile != "" {
+		teams, err := reporter.LoadTeamMapping(*f.teamsFile)
+		if err != nil {
+			return err
+		}
+		rep.AddTeamMembers(teams)
+	}
+	return nil
+}
+
+// validate checks the delivery flags before any report is generated
+func (f *deliveryFlags) validate() error {
+	if _, err := f.deliveries(); err != nil {
+		return err
+	}
+	_, err := f.emails()
+	return err
+}
+
+// Longest time spent delivering a report, including retries
+const deliveryTimeout = 5 * time.Minute
+
+// webhookDelivery is a webhook and the format of the payload posted to it
+type webhookDelivery struct {
+	format  string
+	webhook *delivery.Webhook
+}
+
+// deliveries returns the webhooks selected by -slack-webhook and -webhook
+func (f *deliveryFlags) deliveries() ([]webhookDelivery, error) {
+	if *f.retries < 0 {
+		return nil, fmt.Errorf("invalid delivery-retries: must not be negative")
+	}
+
+	var deliveries []webhookDelivery
+	if *f.slackWebhook != "" {
+		if err := validateWebhookURL(*f.slackWebhook); err != nil {
+			return nil, fmt.Errorf("invalid slack-webhook: %w", err)
+		}
+		deliveries = append(deliveries, webhookDelivery{format: "slack", webhook: &delivery.Webhook{
+			URL:      *f.slackWebhook,
+			Attempts: *f.retries + 1,
+		}})
+	}
+	if *f.webhook != "" {
+		if err := validateWebhookURL(*f.webhook); err != nil {
+			return nil, fmt.Errorf("invalid webhook: %w", err)
+		}
+		secret := *f.webhookSecret
+		if secret == "" {
+			secret = os.Getenv("GHREPORTING_WEBHOOK_SECRET")
+		}
+		deliveries = append(deliveries, webhookDelivery{format: "json", webhook: &delivery.Webhook{
+			URL:      *f.webhook,
+			Headers:  f.webhookHeaders.headers(),
+			Secret:   secret,
+			Attempts: *f.retries + 1,
+		}})
+	} else if len(f.webhookHeaders) > 0 || *f.webhookSecret != "" {
+		return nil, fmt.Errorf("-webhook-header and -webhook-secret require -webhook")
+	}
+	return deliveries, nil
+}
+
+// deliver posts the report to every configured webhook and emails it to every
+// configured recipient within deliveryTimeout. All deliveries are attempted even if
+// one fails; the returned error combines every failure.
+func (f *deliveryFlags) deliver(ctx context.Context, rep *reporter.Reporter, report *models.Report) error {
+	deliveries, err := f.deliveries()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, deliveryTimeout)
+	defer cancel()
+
+	var errs []error
+	for _, d := range deliveries {
+		var body bytes.Buffer
+		if err := rep.WriteReport(&body, report, d.format); err != nil {
+			errs = append(errs, fmt.Errorf("%s payload: %w", d.format, err))
+			continue
+		}
+		if err := d.webhook.Send(ctx, body.Bytes()); err != nil {
+			errs = append(errs, fmt.Errorf("%s delivery: %w", d.format, err))
+			continue
+		}
+		slog.Info("Delivered report", "format", d.format, "host", webhookHost(d.webhook.URL))
+	}
+	if err := f.sendEmails(ctx, rep, report); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+func validateWebhookURL(value string) error {
+	u, err := url.Parse(value)
+	if err != nil {
+		return err
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("expected an http or https URL")
+	}
+	return nil
+}
+
+// webhookHost returns the host of a webhook URL for logging; the path may contain a secret token
+func webhookHost(value string) string {
+	if u, err := url.Parse(value); err == nil {
+		return u.Host
+	}
+	return ""
+}
+
+// isSet reports whether a flag was given on the command line
+func (f *outputFlags) isSet(name string) bool {
+	set := false
+	f.fs.Visit(func(fl *flag.Flag) {
+		if fl.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+// outputList collects repeated -outputs flags, each a comma separated list of format=path pairs
+type outputList []reporter.OutputTarget
+
+func (o *outputList) String() string {
+	var pairs []string
+	for _, target := range *o {
+		pairs = append(pairs, target.Format+"="+target.File)
+	}
+	return strings.Join(pairs, ",")
+}
+
+func (o *outputList) Set(value string) error {
+	targets, err := reporter.ParseOutputTargets(value)
+	if err != nil {
+		return err
+	}
+	*o = append(*o, targets...)
+	return nil
+}
+
+// headerList collects repeated "Name: value" flags
+type headerList []string
+
+func (h *headerList) String() string {
+	return strings.Join(*h, ", ")
+}
+
+func (h *headerList) Set(value string) error {
+	name, _, found := strings.Cut(value, ":")
+	if !found || strings.TrimSpace(name) == "" {
+		return fmt.Errorf("expected \"Name: value\", got %q", value)
+	}
+	*h = append(*h, value)
+	return nil
+}
+
+func (h headerList) headers() map[string]string {
+	headers := make(map[string]string, len(h))
+	for _, header := range h {
+		name, value, _ := strings.Cut(header, ":")
+		headers[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	}
+	return headers
+}
+
+// outputs applies the output flags to the reporter and returns the validated output
+// targets selected by -outputs, or by -format and -output
+func (f *outputFlags) outputs(rep *reporter.Reporter) ([]reporter.OutputTarget, error) {
+	rep.SetRunID(*f.runID)
+
+	outputs := []reporter.OutputTarget{{Format: *f.format, File: *f.outputFile}}
+	if len(f.outputList) > 0 {
+		if f.isSet("format") || f.isSet("output") {
+			return nil, fmt.Errorf("-outputs cannot be combined with -format or -output")
+		}
+		outputs = f.outputList
+	}
+	if err := rep.ValidateOutputTargets(outputs); err != nil {
+		return nil, err
+	}
+	return outputs, nil
+}
+
+// exitStatus returns the process exit status for a written report: 1 when it is
+// incomplete, or with -strict when any data was skipped
+func (f *outputFlags) exitStatus(report *models.Report) int {
+	if report.Incomplete != nil {
+		slog.Error("Report is incomplete", "skipped", len(report.Incomplete.SkippedRepositories))
+		return 1
+	}
+	if *f.strict && len(report.Diagnostics) > 0 {
+		slog.Error("Data was skipped and -strict is set", "diagnostics", len(report.Diagnostics))
+		return 1
+	}
+	return 0
+}
+
+// parseDate parses an optional YYYY-MM-DD flag value, returning fallback when empty
+func parseDate(value string, fallback time.Time) (time.Time, error) {
+	if value == "" {
+		return fallback, nil
+	}
+	return time.Parse("2006-01-02", value)
+}
+
+// splitList splits a comma-separated flag value, dropping empty entries
+func splitList(value string) []string {
+	var result []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"testing"
+
+	"ghreporting/internal/reporter"
+)
+
+func TestOutputFlagsOutputs(t *testing.T) {
+	parse := func(args ...string) ([]reporter.OutputTarget, error) {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(io.Discard)
+		f := addOutputFlags(fs)
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		return f.outputs(reporter.NewReporter(nil))
+	}
+
+	// -output is a plain path, even with "=" in it
+	outputs, err := parse("-format", "parquet", "-output", "lake/dt=2024")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(outputs) != 1 || outputs[0].Format != "parquet" || outputs[0].File != "lake/dt=2024" {
+		t.Errorf("Unexpected outputs: %+v", outputs)
+	}
+
+	outputs, err = parse("-outputs", "text=-,json=report.json", "-outputs", "csv=a,b.csv")
+	if err == nil {
+		t.Errorf("Expected error for the incomplete pair, got %+v", outputs)
+	}
+	outputs, err = parse("-outputs", "text=-,json=report.json", "-outputs", "csv=report.csv")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(outputs) != 3 || outputs[2].Format != "csv" {
+		t.Errorf("Unexpected outputs: %+v", outputs)
+	}
+
+	for _, args := range [][]string{
+		{"-outputs", "json=report.json", "-format", "csv"},
+		{"-outputs", "json=report.json", "-output", "report.txt"},
+		{"-outputs", "json=report.json,csv=./report.json"},
+	} {
+		if _, err := parse(args...); err == nil {
+			t.Errorf("%v: expected error", args)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+
+	"github.com/google/go-github/v57/github"
+)
+
+// Error categories reported in diagnostics
+const (
+	ErrorRateLimit          = "rate_limit"
+	ErrorSecondaryRateLimit = "secondary_rate_limit"
+	ErrorNotFound           = "not_found"
+	ErrorForbidden          = "forbidden"
+	ErrorUnauthorized       = "unauthorized"
+	ErrorEmptyRepository    = "empty_repository"
+	ErrorServer             = "server_error"
+	ErrorNetwork            = "network"
+	ErrorTimeout            = "timeout"
+	ErrorCanceled           = "canceled"
+	ErrorOther              = "other"
+)
+
+// CommitError records a listed commit whose details could not be fetched
+type CommitError struct {
+	SHA string
+	Err error
+}
+
+// TeamError records a team whose members could not be listed
+type TeamError struct {
+	Team string
+	Err  error
+}
+
+// Categorize classifies an API error for diagnostics
+func Categorize(err error) string {
+	var rateLimit *github.RateLimitError
+	var abuse *github.AbuseRateLimitError
+	var response *github.ErrorResponse
+	var netErr net.Error
+
+	switch {
+	case errors.As(err, &rateLimit):
+		return ErrorRateLimit
+	case errors.As(err, &abuse):
+		return ErrorSecondaryRateLimit
+	case errors.Is(err, context.Canceled):
+		return ErrorCanceled
+	case errors.Is(err, context.DeadlineExceeded):
+		return ErrorTimeout
+	case errors.As(err, &response) && response.Response != nil:
+		switch status := response.Response.StatusCode; {
+		case status == http.StatusNotFound:
+			return ErrorNotFound
+		case status == http.StatusForbidden:
+			return ErrorForbidden
+		case status == http.StatusUnauthorized:
+			return ErrorUnauthorized
+		case status == http.StatusConflict:
+			return ErrorEmptyRepository // GitHub answers 409 when listing commits of an empty repository
+		case status >= 500:
+			return ErrorServer
+		}
+	case errors.As(err, &netErr):
+		if netErr.Timeout() {
+			return ErrorTimeout
+		}
+		return ErrorNetwork
+	}
+	return ErrorOther
+}
@@ -0,0 +1,40 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-github/v57/github"
+)
+
+func TestCategorize(t *testing.T) {
+	response := func(status int) error {
+		return fmt.Errorf("failed to list commits: %w", &github.ErrorResponse{Response: &http.Response{StatusCode: status}})
+	}
+
+	tests := []struct {
+		err      error
+		expected string
+	}{
+		{&github.RateLimitError{}, ErrorRateLimit},
+		{fmt.Errorf("wrapped: %w", &github.AbuseRateLimitError{}), ErrorSecondaryRateLimit},
+		{response(http.StatusNotFound), ErrorNotFound},
+		{response(http.StatusForbidden), ErrorForbidden},
+		{response(http.StatusUnauthorized), ErrorUnauthorized},
+		{response(http.StatusConflict), ErrorEmptyRepository},
+		{response(http.StatusBadGateway), ErrorServer},
+		{response(http.StatusTeapot), ErrorOther},
+		{context.Canceled, ErrorCanceled},
+		{fmt.Errorf("wrapped: %w", context.DeadlineExceeded), ErrorTimeout},
+		{errors.New("boom"), ErrorOther},
+	}
+
+	for _, test := range tests {
+		if category := Categorize(test.err); category != test.expected {
+			t.Errorf("Categorize(%v): expected %s, got %s", test.err, test.expected, category)
+		}
+	}
+}
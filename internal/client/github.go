@@ -3,7 +3,10 @@ package client
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
+	"net/http"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/go-github/v57/github"
@@ -12,26 +15,94 @@ import (
 	"ghreporting/internal/models"
 )
 
+// Fraction of the rate limit below which adaptive concurrency starts scaling down
+const adaptiveHeadroom = 0.5
+
 // GitHubClient wraps the GitHub API client
 type GitHubClient struct {
-	client *github.Client
+	client        *github.Client
+	commitWorkers int
+	adaptive      bool
+	// requestLimiter bounds the requests in flight across every caller of the client
+	requestLimiter *Limiter
+	rateLimit      atomic.Int64
+	rateRemaining  atomic.Int64
+	requests       atomic.Int64
 }
 
 // NewGitHubClient creates a new GitHub client
 func NewGitHubClient(token string) *GitHubClient {
-	var client *github.Client
-	
+	gc := &GitHubClient{commitWorkers: 1}
+	gc.requestLimiter = NewLimiter(defaultMaxRequests, gc.requestScale)
+
+	transport := &limitedTransport{base: http.DefaultTransport, gc: gc}
 	if token != "" {
 		ts := oauth2.StaticTokenSource(
 			&oauth2.Token{AccessToken: token},
 		)
-		tc := oauth2.NewClient(context.Background(), ts)
-		client = github.NewClient(tc)
+		gc.client = github.NewClient(&http.Client{Transport: &oauth2.Transport{Source: ts, Base: transport}})
 	} else {
-		client = github.NewClient(nil)
+		gc.client = github.NewClient(&http.Client{Transport: transport})
 	}
 
-	return &GitHubClient{client: client}
+	return gc
+}
+
+// SetMaxRequests configures how many API requests the client has in flight at once,
+// across all repositories, branches and reports using it
+func (gc *GitHubClient) SetMaxRequests(max int) {
+	gc.requestLimiter = NewLimiter(max, gc.requestScale)
+}
+
+// SetCommitConcurrency configures how many commit details ListCommits fetches at once.
+// In adaptive mode fewer requests are made at once as the remaining rate limit shrinks.
+func (gc *GitHubClient) SetCommitConcurrency(workers int, adaptive bool) {
+	gc.commitWorkers = workers
+	gc.adaptive = adaptive
+}
+
+// ConcurrencyScale returns the fraction of configured concurrency that should be used:
+// 1 while at least half of the rate limit remains, shrinking linearly below that
+func (gc *GitHubClient) ConcurrencyScale() float64 {
+	limit := gc.rateLimit.Load()
+	if limit <= 0 {
+		return 1
+	}
+	headroom := float64(gc.rateRemaining.Load()) / float64(limit)
+	if headroom >= adaptiveHeadroom {
+		return 1
+	}
+	return headroom / adaptiveHeadroom
+}
+
+// Requests returns the number of API requests made so far
+func (gc *GitHubClient) Requests() int64 {
+	return gc.requests.Load()
+}
+
+// RateRemaining returns the remaining and total rate limit from the latest response,
+// or zeros before the first response
+func (gc *GitHubClient) RateRemaining() (remaining, limit int64) {
+	return gc.rateRemaining.Load(), gc.rateLimit.Load()
+}
+
+// observeRate counts an API request and records the rate limit reported by its response
+func (gc *GitHubClient) observeRate(resp *github.Response) {
+	gc.requests.Add(1)
+	if resp == nil || resp.Rate.Limit == 0 {
+		return
+	}
+	gc.rateLimit.Store(int64(resp.Rate.Limit))
+	gc.rateRemaining.Store(int64(resp.Rate.Remaining))
+}
+
+// requestScale scales the requests in flight with the remaining rate limit when
+// adaptive concurrency is enabled
+func (gc *GitHubClient) requestScale() float64 {
+	if !gc.adaptive {
+		return 1
+	}
+	return gc.ConcurrencyScale()
 }
 
 // ListRepositories retrieves all repositories for a user or organization
@@ -44,9 +115,10 @@ func (gc *GitHubClient) ListRepositories(ctx context.Context, target string) ([]
 	// Try as organization first, then as user
 	for {
 		repos, resp, err := gc.client.Repositories.ListByOrg(ctx, target, orgOpt)
+		gc.observeRate(resp)
 		if err != nil {
 			// If org fails, try as user
-			log.Printf("Failed to list org repositories, trying as user: %v", err)
+			slog.Debug("Failed to list organization repositories, trying as user", "target", target, "error", err)
 			return gc.listUserRepositories(ctx, target)
 		}
 
@@ -68,6 +140,7 @@ func (gc *GitHubClient) listUserRepositories(ctx context.Context, target string)
 
 	for {
 		repos, resp, err := gc.client.Repositories.List(ctx, target, opt)
+		gc.observeRate(resp)
 		if err != nil {
 			return nil, fmt.Errorf("failed to list user repositories: %w", err)
 		}
@@ -91,6 +164,7 @@ func (gc *GitHubClient) ListBranches(ctx context.Context, owner, repo string) ([
 
 	for {
 		branches, resp, err := gc.client.Repositories.ListBranches(ctx, owner, repo, opt)
+		gc.observeRate(resp)
 		if err != nil {
 			return nil, fmt.Errorf("failed to list branches for %s/%s: %w", owner, repo, err)
 		}
@@ -113,8 +187,11 @@ func (gc *GitHubClient) ListBranches(ctx context.Context, owner, repo string) ([
 	return result, nil
 }
 
-// ListCommits retrieves commits for a repository branch within a time range
-func (gc *GitHubClient) ListCommits(ctx context.Context, owner, repo, branch string, since, until time.Time) ([]models.Commit, error) {
+// ListCommits retrieves commits for a repository branch within a time range.
+// The range is applied by the GitHub API to commit dates; keep, when not nil, is
+// consulted for every listed commit before its statistics are fetched. Commits whose
+// statistics could not be fetched are left out and returned as CommitErrors.
+func (gc *GitHubClient) ListCommits(ctx context.Context, owner, repo, branch string, since, until time.Time, keep func(models.Commit) bool) ([]models.Commit, []CommitError, error) {
 	var allCommits []*github.RepositoryCommit
 	opt := &github.CommitsListOptions{
 		SHA:   branch,
@@ -125,8 +202,9 @@ func (gc *GitHubClient) ListCommits(ctx context.Context, owner, repo, branch str
 
 	for {
 		commits, resp, err := gc.client.Repositories.ListCommits(ctx, owner, repo, opt)
+		gc.observeRate(resp)
 		if err != nil {
-			return nil, fmt.Errorf("failed to list commits for %s/%s@%s: %w", owner, repo, branch, err)
+			return nil, nil, fmt.Errorf("failed to list commits for %s/%s@%s: %w", owner, repo, branch, err)
 		}
 
 		allCommits = append(allCommits, commits...)
@@ -136,37 +214,180 @@ func (gc *GitHubClient) ListCommits(ctx context.Context, owner, repo, branch str
 		opt.Page = resp.NextPage
 	}
 
-	var result []models.Commit
+	var kept []models.Commit
 	for _, commit := range allCommits {
-		// Get detailed commit information with stats
-		detailedCommit, _, err := gc.client.Repositories.GetCommit(ctx, owner, repo, commit.GetSHA(), nil)
-		if err != nil {
-			log.Printf("Warning: failed to get detailed commit info for %s: %v", commit.GetSHA(), err)
+		converted := convertCommit(commit)
+		if keep != nil && !keep(converted) {
 			continue
 		}
+		kept = append(kept, converted)
+	}
 
-		author := models.Author{
-			Name:  commit.GetCommit().GetAuthor().GetName(),
-			Email: commit.GetCommit().GetAuthor().GetEmail(),
-		}
-		if commit.GetAuthor() != nil {
-			author.Login = commit.GetAuthor().GetLogin()
+	// Get detailed commit information with stats, several commits at a time
+	limiter := NewLimiter(gc.commitWorkers, nil)
+	failures := make([]error, len(kept))
+	var wg sync.WaitGroup
+	for i := range kept {
+		if err := limiter.Acquire(ctx); err != nil {
+			wg.Wait()
+			return nil, nil, err
 		}
+		wg.Add(1)
+		go func(commit *models.Commit, failure *error) {
+			defer wg.Done()
+			defer limiter.Release()
 
-		result = append(result, models.Commit{
-			SHA:     commit.GetSHA(),
-			Message: commit.GetCommit().GetMessage(),
-			Author:  author,
-			Date:    commit.GetCommit().GetAuthor().GetDate().Time,
-			Stats: models.CommitStats{
+			detailedCommit, resp, err := gc.client.Repositories.GetCommit(ctx, owner, repo, commit.SHA, nil)
+			gc.observeRate(resp)
+			if err != nil {
+				slog.Warn("Failed to get commit details", "repo", owner+"/"+repo, "branch", branch, "sha", commit.SHA, "error", err)
+				*failure = err
+				return
+			}
+
+			commit.Stats = models.CommitStats{
 				Additions: detailedCommit.GetStats().GetAdditions(),
 				Deletions: detailedCommit.GetStats().GetDeletions(),
 				Total:     detailedCommit.GetStats().GetTotal(),
-			},
-		})
+			}
+		}(&kept[i], &failures[i])
 	}
+	wg.Wait()
 
-	return result, nil
+	var result []models.Commit
+	var skipped []CommitError
+	for i, commit := range kept {
+		if failures[i] != nil {
+			skipped = append(skipped, CommitError{SHA: commit.SHA, Err: failures[i]})
+			continue
+		}
+		result = append(result, commit)
+	}
+
+	return result, skipped, nil
+}
+
+// FindPullRequest returns the merged pull request that introduced a commit into base,
+// or nil if the commit was pushed directly. The pull request whose merge commit is the
+// given SHA is preferred, which identifies squash and rebase merges unambiguously.
+// Otherwise only pull requests merged into base are considered, so commits that reached
+// it through a sync, release or backport pull request into another branch are not
+// credited to that pull request.
+func (gc *GitHubClient) FindPullRequest(ctx context.Context, owner, repo, base, sha string) (*models.PullRequest, error) {
+	pulls, resp, err := gc.client.PullRequests.ListPullRequestsWithCommit(ctx, owner, repo, sha, &github.ListOptions{PerPage: 100})
+	gc.observeRate(resp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pull requests for %s/%s@%s: %w", owner, repo, sha, err)
+	}
+
+	var merged *github.PullRequest
+	for _, pull := range pulls {
+		if pull.MergedAt == nil {
+			continue
+		}
+		if pull.GetMergeCommitSHA() == sha {
+			merged = pull
+			break
+		}
+		if merged == nil && pull.GetBase().GetRef() == base {
+			merged = pull
+		}
+	}
+	if merged == nil {
+		return nil, nil
+	}
+
+	return &models.PullRequest{
+		Number: merged.GetNumber(),
+		URL:    merged.GetHTMLURL(),
+		// The pull request only identifies its author by login
+		Author: models.Author{Login: merged.GetUser().GetLogin()},
+	}, nil
+}
+
+// ListTeamMembers retrieves the members of every team of an organization, keyed by
+// team name. Teams whose members could not be listed are left out and returned as
+// TeamErrors. Listing teams requires a token with the read:org scope.
+func (gc *GitHubClient) ListTeamMembers(ctx context.Context, org string) (map[string][]string, []TeamError, error) {
+	var allTeams []*github.Team
+	opt := &github.ListOptions{PerPage: 100}
+
+	for {
+		teams, resp, err := gc.client.Teams.ListTeams(ctx, org, opt)
+		gc.observeRate(resp)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to list teams for %s: %w", org, err)
+		}
+
+		allTeams = append(allTeams, teams...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+
+	result := make(map[string][]string, len(allTeams))
+	var skipped []TeamError
+	for _, team := range allTeams {
+		members, err := gc.listTeamMembers(ctx, org, team.GetSlug())
+		if err != nil {
+			skipped = append(skipped, TeamError{Team: team.GetName(), Err: err})
+			continue
+		}
+		result[team.GetName()] = members
+	}
+
+	return result, skipped, nil
+}
+
+// listTeamMembers retrieves the logins of the members of one team
+func (gc *GitHubClient) listTeamMembers(ctx context.Context, org, slug string) ([]string, error) {
+	opt := &github.TeamListTeamMembersOptions{
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+	members := []string{}
+	for {
+		users, resp, err := gc.client.Teams.ListTeamMembersBySlug(ctx, org, slug, opt)
+		gc.observeRate(resp)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list members of team %s: %w", slug, err)
+		}
+
+		for _, user := range users {
+			members = append(members, user.GetLogin())
+		}
+		if resp.NextPage == 0 {
+			return members, nil
+		}
+		opt.Page = resp.NextPage
+	}
+}
+
+func convertCommit(commit *github.RepositoryCommit) models.Commit {
+	author := models.Author{
+		Name:  commit.GetCommit().GetAuthor().GetName(),
+		Email: commit.GetCommit().GetAuthor().GetEmail(),
+	}
+	if commit.GetAuthor() != nil {
+		author.Login = commit.GetAuthor().GetLogin()
+	}
+
+	committer := models.Author{
+		Name:  commit.GetCommit().GetCommitter().GetName(),
+		Email: commit.GetCommit().GetCommitter().GetEmail(),
+	}
+	if commit.GetCommitter() != nil {
+		committer.Login = commit.GetCommitter().GetLogin()
+	}
+
+	return models.Commit{
+		SHA:        commit.GetSHA(),
+		Message:    commit.GetCommit().GetMessage(),
+		Author:     author,
+		Date:       commit.GetCommit().GetAuthor().GetDate().Time,
+		Committer:  committer,
+		CommitDate: commit.GetCommit().GetCommitter().GetDate().Time,
+	}
 }
 
 func (gc *GitHubClient) convertRepositories(repos []*github.Repository) []models.Repository {
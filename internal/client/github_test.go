@@ -0,0 +1,77 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestFindPullRequest(t *testing.T) {
+	pulls := map[string]string{
+		// Merged into main through a release pull request and a backport
+		"a1": `[{"number": 1, "base": {"ref": "release"}, "merged_at": "2024-01-02T00:00:00Z", "merge_commit_sha": "x", "user": {"login": "releaser"}},
+			{"number": 2, "base": {"ref": "main"}, "merged_at": "2024-01-03T00:00:00Z", "merge_commit_sha": "y", "user": {"login": "alice"}}]`,
+		// Reached main only through a sync pull request into another branch
+		"b2": `[{"number": 3, "base": {"ref": "develop"}, "merged_at": "2024-01-02T00:00:00Z", "merge_commit_sha": "z", "user": {"login": "bot"}}]`,
+		// The squash commit of a pull request
+		"c3": `[{"number": 4, "base": {"ref": "develop"}, "merged_at": "2024-01-02T00:00:00Z", "merge_commit_sha": "c3", "user": {"login": "bob"}}]`,
+	}
+	gc := testClient(t, func(w http.ResponseWriter, r *http.Request) {
+		sha := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/repos/owner/repo/commits/"), "/pulls")
+		fmt.Fprint(w, pulls[sha])
+	})
+
+	tests := []struct {
+		sha    string
+		number int
+		login  string
+	}{
+		{"a1", 2, "alice"},
+		{"b2", 0, ""},
+		{"c3", 4, "bob"},
+	}
+	for _, tt := range tests {
+		pr, err := gc.FindPullRequest(context.Background(), "owner", "repo", "main", tt.sha)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.sha, err)
+		}
+		if tt.number == 0 {
+			if pr != nil {
+				t.Errorf("%s: expected no pull request, got %+v", tt.sha, pr)
+			}
+			continue
+		}
+		if pr == nil || pr.Number != tt.number || pr.Author.Login != tt.login || pr.Author.Name != "" {
+			t.Errorf("%s: expected pull request %d by %s, got %+v", tt.sha, tt.number, tt.login, pr)
+		}
+	}
+}
+
+func TestListTeamMembersSkipsFailingTeams(t *testing.T) {
+	gc := testClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/orgs/acme/teams":
+			fmt.Fprint(w, `[{"name": "Platform", "slug": "platform"}, {"name": "Secret", "slug": "secret"}, {"name": "Web", "slug": "web"}]`)
+		case "/orgs/acme/teams/platform/members":
+			fmt.Fprint(w, `[{"login": "alice"}, {"login": "bob"}]`)
+		case "/orgs/acme/teams/web/members":
+			fmt.Fprint(w, `[{"login": "carol"}]`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message": "Not Found"}`)
+		}
+	})
+
+	teams, failures, err := gc.ListTeamMembers(context.Background(), "acme")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(teams) != 2 || len(teams["Platform"]) != 2 || len(teams["Web"]) != 1 {
+		t.Errorf("Expected the Platform and Web teams, got %v", teams)
+	}
+	if len(failures) != 1 || failures[0].Team != "Secret" || Categorize(failures[0].Err) != ErrorNotFound {
+		t.Errorf("Expected the Secret team to fail as not found, got %v", failures)
+	}
+}
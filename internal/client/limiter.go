@@ -0,0 +1,76 @@
+package client
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// Limiter bounds the number of concurrent operations. When a scale function is set the
+// bound shrinks proportionally to it, so callers slow down as rate-limit headroom shrinks.
+type Limiter struct {
+	mu     sync.Mutex
+	max    int
+	active int
+	scale  func() float64
+	wake   chan struct{}
+}
+
+// NewLimiter creates a limiter allowing max concurrent operations (at least one).
+// scale, when not nil, returns the fraction of max currently allowed.
+func NewLimiter(max int, scale func() float64) *Limiter {
+	if max < 1 {
+		max = 1
+	}
+	return &Limiter{max: max, scale: scale, wake: make(chan struct{})}
+}
+
+// Acquire blocks until an operation may start or the context is done
+func (l *Limiter) Acquire(ctx context.Context) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		l.mu.Lock()
+		if l.active < l.Limit() {
+			l.active++
+			l.mu.Unlock()
+			return nil
+		}
+		wake := l.wake
+		l.mu.Unlock()
+
+		// The limit can also grow when the rate limit resets, so poll as well
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-wake:
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// Release marks an operation started with Acquire as finished
+func (l *Limiter) Release() {
+	l.mu.Lock()
+	l.active--
+	close(l.wake)
+	l.wake = make(chan struct{})
+	l.mu.Unlock()
+}
+
+// Limit returns the number of operations currently allowed to run at once
+func (l *Limiter) Limit() int {
+	if l.scale == nil {
+		return l.max
+	}
+	limit := int(math.Ceil(float64(l.max) * l.scale()))
+	if limit < 1 {
+		return 1
+	}
+	if limit > l.max {
+		return l.max
+	}
+	return limit
+}
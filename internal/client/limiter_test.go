@@ -0,0 +1,82 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLimiterBoundsConcurrency(t *testing.T) {
+	limiter := NewLimiter(3, nil)
+	ctx := context.Background()
+
+	var active, peak int32
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := limiter.Acquire(ctx); err != nil {
+				t.Errorf("Unexpected error: %v", err)
+				return
+			}
+			defer limiter.Release()
+
+			current := atomic.AddInt32(&active, 1)
+			for {
+				previous := atomic.LoadInt32(&peak)
+				if current <= previous || atomic.CompareAndSwapInt32(&peak, previous, current) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			atomic.AddInt32(&active, -1)
+		}()
+	}
+	wg.Wait()
+
+	if peak > 3 {
+		t.Errorf("Expected at most 3 concurrent operations, got %d", peak)
+	}
+}
+
+func TestLimiterScale(t *testing.T) {
+	scale := 1.0
+	limiter := NewLimiter(10, func() float64 { return scale })
+
+	tests := []struct {
+		scale    float64
+		expected int
+	}{
+		{1.0, 10},
+		{0.5, 5},
+		{0.01, 1},
+		{0, 1},
+		{2.0, 10},
+	}
+	for _, test := range tests {
+		scale = test.scale
+		if limit := limiter.Limit(); limit != test.expected {
+			t.Errorf("Scale %v: expected limit %d, got %d", test.scale, test.expected, limit)
+		}
+	}
+
+	if NewLimiter(0, nil).Limit() != 1 {
+		t.Error("Expected a minimum limit of 1")
+	}
+}
+
+func TestLimiterAcquireCancelled(t *testing.T) {
+	limiter := NewLimiter(1, nil)
+	if err := limiter.Acquire(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := limiter.Acquire(ctx); err == nil {
+		t.Error("Expected error when the context is done")
+	}
+}
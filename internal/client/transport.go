@@ -0,0 +1,135 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// Number of API requests a client has in flight at once unless configured otherwise
+	defaultMaxRequests = 10
+	// Attempts made after a secondary rate limit before its error is returned
+	maxRateLimitRetries = 3
+	// Longest rate limit wait before its error is returned instead of retrying
+	maxRateLimitWait = 2 * time.Minute
+	// Wait after a secondary rate limit response without a Retry-After header, as
+	// recommended by GitHub
+	secondaryRateLimitWait = time.Minute
+)
+
+// limitedTransport bounds the number of requests a client has in flight and waits out
+// secondary rate limits before retrying. Every caller of the client waits while a rate
+// limit is being waited out, so concurrent reports back off together.
+type limitedTransport struct {
+	base http.RoundTripper
+	gc   *GitHubClient
+
+	mu          sync.Mutex
+	pausedUntil time.Time
+}
+
+func (t *limitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	limiter := t.gc.requestLimiter
+	for attempt := 0; ; attempt++ {
+		if err := t.waitPause(req.Context()); err != nil {
+			return nil, err
+		}
+		if err := limiter.Acquire(req.Context()); err != nil {
+			return nil, err
+		}
+
+		resp, err := t.base.RoundTrip(req)
+		if err != nil {
+			limiter.Release()
+			return nil, err
+		}
+
+		wait, limited := rateLimitWait(resp, time.Now())
+		if !limited || attempt == maxRateLimitRetries || wait > maxRateLimitWait || req.Method != http.MethodGet {
+			// The request stays in flight until its body is consumed
+			resp.Body = &releasingBody{ReadCloser: resp.Body, release: limiter.Release}
+			return resp, nil
+		}
+
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		limiter.Release()
+		slog.Warn("GitHub rate limit hit, backing off", "path", req.URL.Path, "wait", wait, "attempt", attempt+1)
+		t.pause(wait)
+	}
+}
+
+// pause holds back every request until wait has passed
+func (t *limitedTransport) pause(wait time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if until := time.Now().Add(wait); until.After(t.pausedUntil) {
+		t.pausedUntil = until
+	}
+}
+
+// waitPause blocks while requests are paused or until the context is done
+func (t *limitedTransport) waitPause(ctx context.Context) error {
+	for {
+		t.mu.Lock()
+		wait := time.Until(t.pausedUntil)
+		t.mu.Unlock()
+		if wait <= 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// rateLimitWait returns how long to wait before retrying a response rejected by a
+// primary or secondary rate limit, and whether it was rejected by one
+func rateLimitWait(resp *http.Response, now time.Time) (time.Duration, bool) {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
+		if err != nil {
+			return 0, false
+		}
+		return max(time.Unix(reset, 0).Sub(now), 0), true
+	}
+
+	// Secondary rate limits are otherwise only identified by their message. The body
+	// is restored for the caller.
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err == nil && bytes.Contains(bytes.ToLower(body), []byte("secondary rate limit")) {
+		return secondaryRateLimitWait, true
+	}
+	return 0, false
+}
+
+// releasingBody releases a request limiter slot once the response body is closed
+type releasingBody struct {
+	io.ReadCloser
+	release func()
+	once    sync.Once
+}
+
+func (b *releasingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err
+}
@@ -0,0 +1,124 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testClient returns a client sending its API requests to handler
+func testClient(t *testing.T, handler http.HandlerFunc) *GitHubClient {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	gc := NewGitHubClient("")
+	baseURL, err := url.Parse(server.URL + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	gc.client.BaseURL = baseURL
+	return gc
+}
+
+func TestClientBoundsRequestsInFlight(t *testing.T) {
+	var active, peak int32
+	gc := testClient(t, func(w http.ResponseWriter, r *http.Request) {
+		current := atomic.AddInt32(&active, 1)
+		for {
+			previous := atomic.LoadInt32(&peak)
+			if current <= previous || atomic.CompareAndSwapInt32(&peak, previous, current) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddInt32(&active, -1)
+		fmt.Fprint(w, `[{"name": "main"}]`)
+	})
+	gc.SetMaxRequests(2)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := gc.ListBranches(context.Background(), "owner", "repo"); err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if peak > 2 {
+		t.Errorf("Expected at most 2 requests in flight, got %d", peak)
+	}
+}
+
+func TestClientRetriesSecondaryRateLimits(t *testing.T) {
+	secondaryRateLimit := func(w http.ResponseWriter, retryAfter string) {
+		w.Header().Set("Retry-After", retryAfter)
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `{"message": "You have exceeded a secondary rate limit", "documentation_url": "https://docs.github.com/rest/overview/resources-in-the-rest-api#secondary-rate-limits"}`)
+	}
+
+	var requests atomic.Int32
+	gc := testClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			secondaryRateLimit(w, "0")
+			return
+		}
+		fmt.Fprint(w, `[{"name": "main"}]`)
+	})
+	branches, err := gc.ListBranches(context.Background(), "owner", "repo")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(branches) != 1 || requests.Load() != 2 {
+		t.Errorf("Expected the request to be retried once, got %d requests and %v", requests.Load(), branches)
+	}
+
+	// Waits longer than the retry limit are returned as errors straight away
+	requests.Store(0)
+	gc = testClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		secondaryRateLimit(w, "3600")
+	})
+	_, err = gc.ListBranches(context.Background(), "owner", "repo")
+	if Categorize(err) != ErrorSecondaryRateLimit || requests.Load() != 1 {
+		t.Errorf("Expected a secondary rate limit error after 1 request, got %v after %d", err, requests.Load())
+	}
+}
+
+func TestRateLimitWait(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	response := func(status int, headers map[string]string) *http.Response {
+		resp := &http.Response{StatusCode: status, Header: make(http.Header), Body: http.NoBody}
+		for name, value := range headers {
+			resp.Header.Set(name, value)
+		}
+		return resp
+	}
+
+	tests := []struct {
+		name    string
+		resp    *http.Response
+		wait    time.Duration
+		limited bool
+	}{
+		{"success", response(http.StatusOK, nil), 0, false},
+		{"retry after", response(http.StatusTooManyRequests, map[string]string{"Retry-After": "30"}), 30 * time.Second, true},
+		{"primary limit", response(http.StatusForbidden, map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": "1700000090"}), 90 * time.Second, true},
+		{"forbidden", response(http.StatusForbidden, nil), 0, false},
+	}
+	for _, tt := range tests {
+		wait, limited := rateLimitWait(tt.resp, now)
+		if wait != tt.wait || limited != tt.limited {
+			t.Errorf("%s: expected %v, %v, got %v, %v", tt.name, tt.wait, tt.limited, wait, limited)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+// Package daemon runs report jobs on cron schedules.
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	"ghreporting/internal/reporter"
+)
+
+// Default history file, relative to the configuration file
+const defaultHistory = "ghreporting-history.jsonl"
+
+// Config lists the scheduled report jobs
+type Config struct {
+	History string `json:"history"` // JSON lines file every run is appended to
+	Jobs    []Job  `json:"jobs"`
+}
+
+// Job is one scheduled report
+type Job struct {
+	Name        string   `json:"name"`
+	Schedule    string   `json:"schedule"`           // Cron expression, e.g. "0 8 * * MON", or a descriptor such as "@daily"
+	Timezone    string   `json:"timezone,omitempty"` // IANA time zone of the schedule (default: local time)
+	Target      string   `json:"target"`
+	Days        int      `json:"days,omitempty"` // Length of the report period ending at the scheduled time (default: 30)
+	Outputs     []string `json:"outputs"`        // format=path pairs; paths may contain {job}, {target}, {date} and {timestamp}
+	AllBranches bool     `json:"all_branches,omitempty"`
+	ResolvePRs  bool     `json:"resolve_prs,omitempty"`
+
+	location *time.Location
+	targets  []reporter.OutputTarget
+}
+
+// LoadConfig reads and validates a daemon configuration file
+func LoadConfig(path string, rep *reporter.Reporter) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	var config Config
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+
+	if config.History == "" {
+		config.History = defaultHistory
+	}
+	if !filepath.IsAbs(config.History) {
+		config.History = filepath.Join(filepath.Dir(path), config.History)
+	}
+
+	if err := config.validate(rep); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// validate checks every job and resolves its time zone and outputs
+func (c *Config) validate(rep *reporter.Reporter) error {
+	if len(c.Jobs) == 0 {
+		return fmt.Errorf("no jobs configured")
+	}
+
+	names := make(map[string]bool)
+	for i := range c.Jobs {
+		job := &c.Jobs[i]
+		if job.Name == "" {
+			return fmt.Errorf("job %d: name is required", i+1)
+		}
+		if names[job.Name] {
+			return fmt.Errorf("job %s: duplicate name", job.Name)
+		}
+		names[job.Name] = true
+
+		if err := job.validate(rep); err != nil {
+			return fmt.Errorf("job %s: %w", job.Name, err)
+		}
+	}
+	return nil
+}
+
+func (j *Job) validate(rep *reporter.Reporter) error {
+	if j.Target == "" {
+		return fmt.Errorf("target is required")
+	}
+	if j.Days < 0 {
+		return fmt.Errorf("days must not be negative")
+	}
+	if j.Days == 0 {
+		j.Days = 30
+	}
+
+	j.location = time.Local
+	if j.Timezone != "" {
+		location, err := time.LoadLocation(j.Timezone)
+		if err != nil {
+			return fmt.Errorf("invalid timezone: %w", err)
+		}
+		j.location = location
+	}
+	if _, err := cron.ParseStandard(j.Schedule); err != nil {
+		return fmt.Errorf("invalid schedule %q: %w", j.Schedule, err)
+	}
+
+	if len(j.Outputs) == 0 {
+		return fmt.Errorf("no outputs configured")
+	}
+	var targets []reporter.OutputTarget
+	for _, output := range j.Outputs {
+		target, err := reporter.ParseOutputTarget(output)
+		if err != nil {
+			return err
+		}
+		if target.File == "" || target.File == "-" {
+			return fmt.Errorf("%s output requires a destination file", target.Format)
+		}
+		targets = append(targets, target)
+	}
+	if err := rep.ValidateOutputTargets(targets); err != nil {
+		return err
+	}
+	j.targets = targets
+	return nil
+}
+
+// spec returns the cron specification in the job's time zone
+func (j *Job) spec() string {
+	return "CRON_TZ=" + j.location.String() + " " + j.Schedule
+}
+
+// outputTargets returns the job's outputs for a run at the given time. Paths without
+// a {date} or {timestamp} placeholder get the timestamp appended to the file name so
+// earlier runs are never overwritten.
+func (j *Job) outputTargets(at time.Time) []reporter.OutputTarget {
+	at = at.In(j.location)
+	timestamp := at.Format("20060102T150405")
+	replacer := strings.NewReplacer(
+		"{job}", j.Name,
+		"{target}", j.Target,
+		"{date}", at.Format("2006-01-02"),
+		"{timestamp}", timestamp,
+	)
+
+	targets := make([]reporter.OutputTarget, len(j.targets))
+	for i, target := range j.targets {
+		path := target.File
+		if !strings.Contains(path, "{date}") && !strings.Contains(path, "{timestamp}") {
+			ext := filepath.Ext(path)
+			path = strings.TrimSuffix(path, ext) + "-" + timestamp + ext
+		}
+		targets[i] = reporter.OutputTarget{Format: target.Format, File: replacer.Replace(path)}
+	}
+	return targets
+}
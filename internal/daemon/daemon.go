@@ -0,0 +1,223 @@
+package daemon
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	"ghreporting/internal/models"
+	"ghreporting/internal/reporter"
+)
+
+// Run outcomes recorded in the history
+const (
+	StatusSuccess    = "success"
+	StatusIncomplete = "incomplete"
+	StatusFailed     = "failed"
+)
+
+// Longest time a job spends delivering its report, including retries
+const deliveryTimeout = 5 * time.Minute
+
+// Generator produces the report of a job for a period
+type Generator func(ctx context.Context, job Job, since, until time.Time) (*models.Report, error)
+
+// Deliverer sends a written report to external services such as webhooks
+type Deliverer func(ctx context.Context, report *models.Report) error
+
+// Run is one entry of the run history
+type Run struct {
+	Job          string    `json:"job"`
+	Target       string    `json:"target"`
+	Since        time.Time `json:"since"`
+	Until        time.Time `json:"until"`
+	Started      time.Time `json:"started"`
+	Finished     time.Time `json:"finished"`
+	Status       string    `json:"status"`
+	Error        string    `json:"error,omitempty"`
+	Outputs      []string  `json:"outputs,omitempty"`
+	Repositories int       `json:"repositories"`
+	Contributors int       `json:"contributors"`
+	Diagnostics  int       `json:"diagnostics"`
+}
+
+// Daemon runs the configured jobs on their schedules
+type Daemon struct {
+	config   *Config
+	rep      *reporter.Reporter
+	generate Generator
+	deliver  Deliverer
+	now      func() time.Time
+
+	deliveryTimeout time.Duration
+
+	historyMu sync.Mutex
+}
+
+// New creates a daemon rendering reports with rep
+func New(config *Config, rep *reporter.Reporter, generate Generator) *Daemon {
+	return &Daemon{
+		config:   config,
+		rep:      rep,
+		generate: generate,
+		now:      time.Now,
+
+		deliveryTimeout: deliveryTimeout,
+	}
+}
+
+// SetDelivery configures where reports are sent after their outputs are written
+func (d *Daemon) SetDelivery(deliver Deliverer) {
+	d.deliver = deliver
+}
+
+// Run schedules every job and blocks until ctx is done. Jobs still running are
+// cancelled, write their partial reports and are recorded before Run returns.
+// A job is skipped when its previous run has not finished yet.
+func (d *Daemon) Run(ctx context.Context) error {
+	logger := cronLogger{}
+	scheduler := cron.New(cron.WithChain(cron.Recover(logger), cron.SkipIfStillRunning(logger)), cron.WithLogger(logger))
+
+	for _, job := range d.config.Jobs {
+		id, err := scheduler.AddFunc(job.spec(), func() { d.RunJob(ctx, job) })
+		if err != nil {
+			return fmt.Errorf("failed to schedule job %s: %w", job.Name, err)
+		}
+		slog.Info("Scheduled job", "job", job.Name, "schedule", job.Schedule, "next", scheduler.Entry(id).Next)
+	}
+
+	scheduler.Start()
+	<-ctx.Done()
+	<-scheduler.Stop().Done()
+	return nil
+}
+
+// RunJob generates and writes one job's report for the period ending now, and
+// appends the outcome to the history
+func (d *Daemon) RunJob(ctx context.Context, job Job) Run {
+	started := d.now()
+	until := started.In(job.location)
+	run := Run{
+		Job:     job.Name,
+		Target:  job.Target,
+		Since:   until.AddDate(0, 0, -job.Days),
+		Until:   until,
+		Started: started,
+	}
+
+	slog.Info("Starting job", "job", job.Name, "target", job.Target)
+	err := d.runJob(ctx, job, &run)
+	run.Finished = d.now()
+	switch {
+	case err != nil:
+		run.Status = StatusFailed
+		run.Error = err.Error()
+		slog.Error("Job failed", "job", job.Name, "duration", run.Finished.Sub(started), "error", err)
+	case run.Status == StatusIncomplete:
+		slog.Warn("Job wrote an incomplete report", "job", job.Name, "duration", run.Finished.Sub(started), "error", run.Error)
+	default:
+		run.Status = StatusSuccess
+		slog.Info("Finished job", "job", job.Name, "duration", run.Finished.Sub(started), "outputs", len(run.Outputs))
+	}
+
+	if err := d.record(run); err != nil {
+		slog.Error("Failed to record run history", "job", job.Name, "error", err)
+	}
+	return run
+}
+
+func (d *Daemon) runJob(ctx context.Context, job Job, run *Run) error {
+	report, err := d.generate(ctx, job, run.Since, run.Until)
+	if err != nil {
+		return fmt.Errorf("failed to generate report: %w", err)
+	}
+	run.Repositories = len(report.Repositories)
+	run.Contributors = len(report.Summary)
+	run.Diagnostics = len(report.Diagnostics)
+	if report.Incomplete != nil {
+		run.Status = StatusIncomplete
+		run.Error = report.Incomplete.Reason
+	}
+
+	targets := job.outputTargets(run.Until)
+	for _, target := range targets {
+		if err := os.MkdirAll(filepath.Dir(target.File), 0755); err != nil {
+			return fmt.Errorf("failed to create output directory: %w", err)
+		}
+	}
+	if err := d.rep.OutputReports(report, targets); err != nil {
+		return fmt.Errorf("failed to output report: %w", err)
+	}
+	for _, target := range targets {
+		run.Outputs = append(run.Outputs, target.File)
+	}
+
+	// Deliver even when shutting down, so a partial report still reaches its readers,
+	// but within a bounded time so shutdown and later runs of the job are not blocked
+	if d.deliver != nil {
+		deliverCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), d.deliveryTimeout)
+		defer cancel()
+		if err := d.deliver(deliverCtx, report); err != nil {
+			return fmt.Errorf("failed to deliver report: %w", err)
+		}
+	}
+	return nil
+}
+
+// record appends a run to the history file
+func (d *Daemon) record(run Run) error {
+	d.historyMu.Lock()
+	defer d.historyMu.Unlock()
+
+	line, err := json.Marshal(run)
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(d.config.History, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// LoadHistory reads the runs recorded in a history file, oldest first
+func LoadHistory(path string) ([]Run, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+
+	var runs []Run
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	for decoder.More() {
+		var run Run
+		if err := decoder.Decode(&run); err != nil {
+			return nil, fmt.Errorf("failed to parse history: %w", err)
+		}
+		runs = append(runs, run)
+	}
+	return runs, nil
+}
+
+// cronLogger routes scheduler messages to slog
+type cronLogger struct{}
+
+func (cronLogger) Info(msg string, keysAndValues ...any) {
+	slog.Debug(msg, keysAndValues...)
+}
+
+func (cronLogger) Error(err error, msg string, keysAndValues ...any) {
+	slog.Error(msg, append(keysAndValues, "error", err)...)
+}
@@ -0,0 +1,187 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"ghreporting/internal/models"
+	"ghreporting/internal/reporter"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "daemon.json")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadConfig(t *testing.T) {
+	path := writeConfig(t, `{
+		"jobs": [{
+			"name": "weekly",
+			"schedule": "0 8 * * MON",
+			"timezone": "Europe/Berlin",
+			"target": "myorg",
+			"days": 7,
+			"outputs": ["json=reports/{job}-{date}.json", "html=reports/weekly.html"]
+		}]
+	}`)
+
+	config, err := LoadConfig(path, &reporter.Reporter{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if config.History != filepath.Join(filepath.Dir(path), defaultHistory) {
+		t.Errorf("Expected history next to the config, got %s", config.History)
+	}
+	job := config.Jobs[0]
+	if job.location.String() != "Europe/Berlin" || job.Days != 7 || len(job.targets) != 2 {
+		t.Errorf("Unexpected job: %+v", job)
+	}
+	if job.spec() != "CRON_TZ=Europe/Berlin 0 8 * * MON" {
+		t.Errorf("Unexpected spec %q", job.spec())
+	}
+}
+
+func TestLoadConfigErrors(t *testing.T) {
+	valid := `"name": "a", "schedule": "@daily", "target": "myorg", "outputs": ["json=r.json"]`
+	tests := map[string]string{
+		"no jobs":        `{"jobs": []}`,
+		"missing name":   `{"jobs": [{"schedule": "@daily", "target": "myorg", "outputs": ["json=r.json"]}]}`,
+		"duplicate name": `{"jobs": [{` + valid + `}, {` + valid + `}]}`,
+		"bad schedule":   `{"jobs": [{"name": "a", "schedule": "every monday", "target": "myorg", "outputs": ["json=r.json"]}]}`,
+		"bad timezone":   `{"jobs": [{` + valid + `, "timezone": "Mars/Olympus"}]}`,
+		"no outputs":     `{"jobs": [{"name": "a", "schedule": "@daily", "target": "myorg"}]}`,
+		"stdout output":  `{"jobs": [{"name": "a", "schedule": "@daily", "target": "myorg", "outputs": ["json=-"]}]}`,
+		"bad format":     `{"jobs": [{"name": "a", "schedule": "@daily", "target": "myorg", "outputs": ["pdf=r.pdf"]}]}`,
+		"invalid json":   `{"jobs": [`,
+	}
+	for name, content := range tests {
+		if _, err := LoadConfig(writeConfig(t, content), &reporter.Reporter{}); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestOutputTargetsTimestamped(t *testing.T) {
+	job := Job{Name: "weekly", Schedule: "@weekly", Timezone: "UTC", Target: "myorg", Days: 7, Outputs: []string{
+		"json=out/{target}-{date}.json",
+		"html=out/report.html",
+		"parquet=out/parquet",
+	}}
+	if err := job.validate(&reporter.Reporter{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	targets := job.outputTargets(time.Date(2024, 3, 4, 8, 0, 0, 0, time.UTC))
+	expected := []string{"out/myorg-2024-03-04.json", "out/report-20240304T080000.html", "out/parquet-20240304T080000"}
+	for i, target := range targets {
+		if target.File != expected[i] {
+			t.Errorf("Expected %s, got %s", expected[i], target.File)
+		}
+	}
+}
+
+func TestRunJobWritesOutputsAndHistory(t *testing.T) {
+	dir := t.TempDir()
+	config := &Config{
+		History: filepath.Join(dir, "history.jsonl"),
+		Jobs: []Job{
+			{Name: "ok", Schedule: "@daily", Target: "myorg", Days: 7, Outputs: []string{"json=" + filepath.Join(dir, "reports", "{job}-{timestamp}.json")}},
+			{Name: "broken", Schedule: "@daily", Target: "broken", Outputs: []string{"json=" + filepath.Join(dir, "broken.json")}},
+		},
+	}
+	if err := config.validate(&reporter.Reporter{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	generate := func(ctx context.Context, job Job, since, until time.Time) (*models.Report, error) {
+		if job.Target == "broken" {
+			return nil, fmt.Errorf("API unavailable")
+		}
+		return &models.Report{
+			Target:       job.Target,
+			Period:       models.Period{Since: since, Until: until},
+			Repositories: []models.Repository{{Name: "repo1", FullName: "myorg/repo1"}},
+		}, nil
+	}
+
+	d := New(config, &reporter.Reporter{}, generate)
+	var delivered []string
+	d.SetDelivery(func(ctx context.Context, report *models.Report) error {
+		delivered = append(delivered, report.Target)
+		return nil
+	})
+	now := time.Date(2024, 3, 4, 8, 0, 0, 0, time.Local)
+	d.now = func() time.Time { return now }
+
+	run := d.RunJob(context.Background(), config.Jobs[0])
+	if run.Status != StatusSuccess || run.Repositories != 1 {
+		t.Errorf("Unexpected run: %+v", run)
+	}
+	if !run.Since.Equal(now.AddDate(0, 0, -7)) {
+		t.Errorf("Expected a 7 day period, got since %v", run.Since)
+	}
+	expected := filepath.Join(dir, "reports", "ok-20240304T080000.json")
+	if len(run.Outputs) != 1 || run.Outputs[0] != expected {
+		t.Errorf("Expected output %s, got %v", expected, run.Outputs)
+	}
+	if _, err := os.Stat(expected); err != nil {
+		t.Errorf("Expected report to be written: %v", err)
+	}
+
+	if len(delivered) != 1 || delivered[0] != "myorg" {
+		t.Errorf("Expected the report to be delivered once, got %v", delivered)
+	}
+
+	if run := d.RunJob(context.Background(), config.Jobs[1]); run.Status != StatusFailed || !strings.Contains(run.Error, "API unavailable") {
+		t.Errorf("Expected failed run, got %+v", run)
+	}
+
+	runs, err := LoadHistory(config.History)
+	if err != nil {
+		t.Fatalf("Failed to load history: %v", err)
+	}
+	if len(runs) != 2 || runs[0].Job != "ok" || runs[1].Status != StatusFailed {
+		t.Errorf("Unexpected history: %+v", runs)
+	}
+}
+
+func TestRunJobBoundsDelivery(t *testing.T) {
+	dir := t.TempDir()
+	config := &Config{
+		History: filepath.Join(dir, "history.jsonl"),
+		Jobs:    []Job{{Name: "ok", Schedule: "@daily", Target: "myorg", Outputs: []string{"json=" + filepath.Join(dir, "{job}.json")}}},
+	}
+	if err := config.validate(&reporter.Reporter{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	generate := func(ctx context.Context, job Job, since, until time.Time) (*models.Report, error) {
+		return &models.Report{Target: job.Target, Period: models.Period{Since: since, Until: until}}, nil
+	}
+	d := New(config, &reporter.Reporter{}, generate)
+	d.deliveryTimeout = 50 * time.Millisecond
+	d.SetDelivery(func(ctx context.Context, report *models.Report) error {
+		<-ctx.Done() // An endpoint that never answers
+		return ctx.Err()
+	})
+
+	// Shutting down does not cancel delivery, but delivery still ends
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	start := time.Now()
+	run := d.RunJob(ctx, config.Jobs[0])
+	if run.Status != StatusFailed || !strings.Contains(run.Error, "deadline exceeded") {
+		t.Errorf("Expected delivery to time out, got %+v", run)
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Errorf("Expected delivery to be bounded, took %v", time.Since(start))
+	}
+}
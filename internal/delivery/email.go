@@ -0,0 +1,332 @@
+package delivery
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SMTP connection security modes
+const (
+	TLSStartTLS = "starttls" // Plain connection upgraded with STARTTLS (required)
+	TLSImplicit = "implicit" // TLS from the first byte, usually port 465
+	TLSNone     = "none"     // Unencrypted, for local relays only
+)
+
+// SMTP is the mail server reports are sent through
+type SMTP struct {
+	Host        string `json:"host"`
+	Port        int    `json:"port,omitempty"` // Default 587, or 465 with implicit TLS
+	Username    string `json:"username,omitempty"`
+	Password    string `json:"password,omitempty"`     // Falls back to the SMTP_PASSWORD env var
+	TLS         string `json:"tls,omitempty"`          // starttls (default), implicit or none
+	InsecureTLS bool   `json:"insecure_tls,omitempty"` // Skip certificate verification
+
+	tlsConfig *tls.Config   // Overrides the TLS configuration in tests
+	timeout   time.Duration // Overrides sendTimeout in tests
+}
+
+// Longest time an SMTP session may take, so a stalled server cannot block a run
+const sendTimeout = 2 * time.Minute
+
+// EmailConfig configures email delivery: the server, the sender and who receives which part of the report
+type EmailConfig struct {
+	SMTP        SMTP        `json:"smtp"`
+	From        string      `json:"from"`
+	Subject     string      `json:"subject,omitempty"`     // May contain {target}, {since}, {until} and {name}
+	Attachments []string    `json:"attachments,omitempty"` // Report formats attached to every email, e.g. json, csv
+	Recipients  []Recipient `json:"recipients"`
+}
+
+// Recipient is a group of addresses receiving the same, optionally filtered, report
+type Recipient struct {
+	Name         string   `json:"name,omitempty"` // Shown in the subject, e.g. a team name
+	To           []string `json:"to"`
+	Repositories []string `json:"repositories,omitempty"` // Glob patterns the report is narrowed to
+	Contributors []string `json:"contributors,omitempty"` // Contributor logins, emails or names the report is narrowed to
+}
+
+// Default subject of report emails
+const defaultSubject = "GitHub Activity Report: {target} ({since} to {until})"
+
+// LoadEmailConfig reads and validates an email delivery configuration file
+func LoadEmailConfig(path string) (*EmailConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read email config: %w", err)
+	}
+
+	var config EmailConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse email config: %w", err)
+	}
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+func (c *EmailConfig) validate() error {
+	if c.SMTP.Host == "" {
+		return fmt.Errorf("smtp host is required")
+	}
+	switch c.SMTP.TLS {
+	case "":
+		c.SMTP.TLS = TLSStartTLS
+	case TLSStartTLS, TLSImplicit, TLSNone:
+	default:
+		return fmt.Errorf("invalid smtp tls mode %q (expected starttls, implicit or none)", c.SMTP.TLS)
+	}
+	if c.SMTP.Port == 0 {
+		c.SMTP.Port = 587
+		if c.SMTP.TLS == TLSImplicit {
+			c.SMTP.Port = 465
+		}
+	}
+	if c.SMTP.Password == "" {
+		c.SMTP.Password = os.Getenv("SMTP_PASSWORD")
+	}
+
+	if _, err := mail.ParseAddress(c.From); err != nil {
+		return fmt.Errorf("invalid from address %q: %w", c.From, err)
+	}
+	if c.Subject == "" {
+		c.Subject = defaultSubject
+	}
+	if len(c.Recipients) == 0 {
+		return fmt.Errorf("no recipients configured")
+	}
+	for i, recipient := range c.Recipients {
+		if len(recipient.To) == 0 {
+			return fmt.Errorf("recipient %d: no addresses", i+1)
+		}
+		for _, address := range recipient.To {
+			if _, err := mail.ParseAddress(address); err != nil {
+				return fmt.Errorf("recipient %d: invalid address %q: %w", i+1, address, err)
+			}
+		}
+		for _, pattern := range recipient.Repositories {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("recipient %d: invalid repository pattern %q: %w", i+1, pattern, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Email is a report message with an HTML body, a plain text fallback and attachments
+type Email struct {
+	From        string
+	To          []string
+	Subject     string
+	HTML        string
+	Text        string
+	Attachments []Attachment
+}
+
+// Attachment is a file attached to an Email
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
+// Send delivers the email through the server
+func (s *SMTP) Send(ctx context.Context, email *Email) error {
+	message, err := email.Bytes()
+	if err != nil {
+		return err
+	}
+
+	addr := net.JoinHostPort(s.Host, strconv.Itoa(s.Port))
+	tlsConfig := s.tlsConfig
+	if tlsConfig == nil {
+		tlsConfig = &tls.Config{ServerName: s.Host, InsecureSkipVerify: s.InsecureTLS}
+	}
+
+	dialer := &net.Dialer{Timeout: 30 * time.Second}
+	var conn net.Conn
+	if s.TLS == TLSImplicit {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: tlsConfig}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", addr, err)
+	}
+	timeout := s.timeout
+	if timeout <= 0 {
+		timeout = sendTimeout
+	}
+	deadline := time.Now().Add(timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	conn.SetDeadline(deadline)
+
+	client, err := smtp.NewClient(conn, s.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to start SMTP session: %w", err)
+	}
+	defer client.Close()
+
+	if s.TLS == TLSStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return fmt.Errorf("%s does not support STARTTLS", addr)
+		}
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return fmt.Errorf("failed to start TLS: %w", err)
+		}
+	}
+	if s.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", s.Username, s.Password, s.Host)); err != nil {
+			return fmt.Errorf("failed to authenticate: %w", err)
+		}
+	}
+
+	from, err := mail.ParseAddress(email.From)
+	if err != nil {
+		return fmt.Errorf("invalid from address: %w", err)
+	}
+	if err := client.Mail(from.Address); err != nil {
+		return fmt.Errorf("failed to set sender: %w", err)
+	}
+	for _, to := range email.To {
+		address, err := mail.ParseAddress(to)
+		if err != nil {
+			return fmt.Errorf("invalid recipient %q: %w", to, err)
+		}
+		if err := client.Rcpt(address.Address); err != nil {
+			return fmt.Errorf("recipient %s rejected: %w", address.Address, err)
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+	if _, err := w.Write(message); err != nil {
+		w.Close()
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+	return client.Quit()
+}
+
+// Bytes renders the email as a MIME message: a multipart/mixed message holding a
+// multipart/alternative text and HTML body followed by the attachments
+func (e *Email) Bytes() ([]byte, error) {
+	var buf bytes.Buffer
+	header := func(name, value string) { fmt.Fprintf(&buf, "%s: %s\r\n", name, value) }
+
+	header("From", e.From)
+	header("To", strings.Join(e.To, ", "))
+	header("Subject", mime.QEncoding.Encode("utf-8", e.Subject))
+	header("Date", time.Now().Format(time.RFC1123Z))
+	header("Message-ID", messageID(e.From))
+	header("MIME-Version", "1.0")
+
+	mixed := multipart.NewWriter(&buf)
+	header("Content-Type", `multipart/mixed; boundary="`+mixed.Boundary()+`"`)
+	buf.WriteString("\r\n")
+
+	var alternativeBody bytes.Buffer
+	alternative := multipart.NewWriter(&alternativeBody)
+	for _, part := range []struct{ contentType, content string }{
+		{"text/plain; charset=utf-8", e.Text},
+		{"text/html; charset=utf-8", e.HTML},
+	} {
+		w, err := alternative.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qp := quotedprintable.NewWriter(w)
+		if _, err := qp.Write([]byte(part.content)); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := alternative.Close(); err != nil {
+		return nil, err
+	}
+
+	body, err := mixed.CreatePart(textproto.MIMEHeader{
+		"Content-Type": {`multipart/alternative; boundary="` + alternative.Boundary() + `"`},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := body.Write(alternativeBody.Bytes()); err != nil {
+		return nil, err
+	}
+
+	for _, attachment := range e.Attachments {
+		w, err := mixed.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {attachment.ContentType},
+			"Content-Transfer-Encoding": {"base64"},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename})},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := writeBase64(w, attachment.Data); err != nil {
+			return nil, err
+		}
+	}
+	if err := mixed.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeBase64 writes data base64 encoded in lines of 76 characters, as MIME requires
+func writeBase64(w io.Writer, data []byte) error {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	for len(encoded) > 0 {
+		n := min(len(encoded), 76)
+		if _, err := io.WriteString(w, encoded[:n]+"\r\n"); err != nil {
+			return err
+		}
+		encoded = encoded[n:]
+	}
+	return nil
+}
+
+// messageID returns a unique Message-ID in the sender's domain
+func messageID(from string) string {
+	domain := "localhost"
+	if address, err := mail.ParseAddress(from); err == nil {
+		if _, host, found := strings.Cut(address.Address, "@"); found {
+			domain = host
+		}
+	}
+	random := make([]byte, 12)
+	rand.Read(random)
+	return "<" + hex.EncodeToString(random) + "@" + domain + ">"
+}
@@ -0,0 +1,313 @@
+package delivery
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/mail"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// smtpSession is what the fake server received
+type smtpSession struct {
+	tls  bool
+	auth string
+	from string
+	to   []string
+	data string
+}
+
+// fakeSMTP serves one SMTP session offering STARTTLS and AUTH PLAIN
+func fakeSMTP(t *testing.T, serverTLS *tls.Config) (port int, session <-chan smtpSession) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	result := make(chan smtpSession, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.SetDeadline(time.Now().Add(10 * time.Second))
+
+		var s smtpSession
+		text := textproto.NewConn(conn)
+		text.PrintfLine("220 localhost ESMTP")
+		for {
+			line, err := text.ReadLine()
+			if err != nil {
+				return
+			}
+			command, arg, _ := strings.Cut(line, " ")
+			switch strings.ToUpper(command) {
+			case "EHLO":
+				if s.tls {
+					text.PrintfLine("250-localhost\r\n250 AUTH PLAIN")
+				} else {
+					text.PrintfLine("250-localhost\r\n250 STARTTLS")
+				}
+			case "STARTTLS":
+				text.PrintfLine("220 Ready to start TLS")
+				tlsConn := tls.Server(conn, serverTLS)
+				if err := tlsConn.Handshake(); err != nil {
+					return
+				}
+				s.tls = true
+				text = textproto.NewConn(tlsConn)
+			case "AUTH":
+				credentials, _ := base64.StdEncoding.DecodeString(strings.TrimPrefix(arg, "PLAIN "))
+				s.auth = string(credentials)
+				text.PrintfLine("235 Authenticated")
+			case "MAIL":
+				s.from = arg
+				text.PrintfLine("250 OK")
+			case "RCPT":
+				s.to = append(s.to, arg)
+				text.PrintfLine("250 OK")
+			case "DATA":
+				text.PrintfLine("354 Go ahead")
+				data, _ := text.ReadDotBytes()
+				s.data = string(data)
+				text.PrintfLine("250 Queued")
+			case "QUIT":
+				text.PrintfLine("221 Bye")
+				result <- s
+				return
+			default:
+				text.PrintfLine("502 Unknown command")
+			}
+		}
+	}()
+	return listener.Addr().(*net.TCPAddr).Port, result
+}
+
+// testTLS returns server and client TLS configurations sharing the httptest certificate
+func testTLS(t *testing.T) (server, client *tls.Config) {
+	ts := httptest.NewTLSServer(http.NotFoundHandler())
+	defer ts.Close()
+	client = ts.Client().Transport.(*http.Transport).TLSClientConfig.Clone()
+	client.ServerName = "127.0.0.1"
+	return ts.TLS, client
+}
+
+func testEmail() *Email {
+	return &Email{
+		From:    "Reports <reports@example.com>",
+		To:      []string{"lead@example.com", "Other Lead <other@example.com>"},
+		Subject: "GitHub Activity Report: myorg — week 12",
+		HTML:    "<h1>Report</h1>",
+		Text:    "Report",
+		Attachments: []Attachment{
+			{Filename: "myorg.json", ContentType: "application/json", Data: []byte(`{"target":"myorg"}`)},
+		},
+	}
+}
+
+func TestSMTPSendStartTLSAndAuth(t *testing.T) {
+	serverTLS, clientTLS := testTLS(t)
+	port, sessions := fakeSMTP(t, serverTLS)
+
+	server := &SMTP{Host: "127.0.0.1", Port: port, Username: "bot", Password: "s3cret", TLS: TLSStartTLS, tlsConfig: clientTLS}
+	if err := server.Send(context.Background(), testEmail()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	session := <-sessions
+	if !session.tls {
+		t.Error("Expected the session to be upgraded with STARTTLS")
+	}
+	if session.auth != "\x00bot\x00s3cret" {
+		t.Errorf("Expected PLAIN credentials, got %q", session.auth)
+	}
+	if session.from != "FROM:<reports@example.com>" {
+		t.Errorf("Unexpected sender %q", session.from)
+	}
+	if len(session.to) != 2 || session.to[1] != "TO:<other@example.com>" {
+		t.Errorf("Unexpected recipients %v", session.to)
+	}
+	if !strings.Contains(session.data, "Subject: =?utf-8?q?") {
+		t.Errorf("Expected an encoded subject, got:\n%s", session.data)
+	}
+}
+
+func TestSMTPRequiresStartTLS(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		reader := bufio.NewReader(conn)
+		io.WriteString(conn, "220 localhost ESMTP\r\n")
+		reader.ReadString('\n')
+		io.WriteString(conn, "250 localhost\r\n")
+		reader.ReadString('\n')
+	}()
+
+	server := &SMTP{Host: "127.0.0.1", Port: listener.Addr().(*net.TCPAddr).Port, TLS: TLSStartTLS}
+	if err := server.Send(context.Background(), testEmail()); err == nil || !strings.Contains(err.Error(), "STARTTLS") {
+		t.Errorf("Expected STARTTLS error, got %v", err)
+	}
+}
+
+func TestSMTPSendTimesOut(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	go func() {
+		// Accept the connection but never greet
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		io.Copy(io.Discard, conn)
+	}()
+
+	server := &SMTP{Host: "127.0.0.1", Port: listener.Addr().(*net.TCPAddr).Port, TLS: TLSNone, timeout: 50 * time.Millisecond}
+	start := time.Now()
+	if err := server.Send(context.Background(), testEmail()); err == nil {
+		t.Error("Expected error from a stalled server")
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Errorf("Expected Send to time out, took %v", time.Since(start))
+	}
+}
+
+func TestEmailBytes(t *testing.T) {
+	data, err := testEmail().Bytes()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	message, err := mail.ReadMessage(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Invalid message: %v", err)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(message.Header.Get("Subject"))
+	if err != nil || subject != "GitHub Activity Report: myorg — week 12" {
+		t.Errorf("Unexpected subject %q (%v)", subject, err)
+	}
+
+	_, params, err := mime.ParseMediaType(message.Header.Get("Content-Type"))
+	if err != nil {
+		t.Fatalf("Invalid content type: %v", err)
+	}
+	mixed := multipart.NewReader(message.Body, params["boundary"])
+
+	body, err := mixed.NextPart()
+	if err != nil {
+		t.Fatalf("Missing body part: %v", err)
+	}
+	mediaType, params, _ := mime.ParseMediaType(body.Header.Get("Content-Type"))
+	if mediaType != "multipart/alternative" {
+		t.Fatalf("Expected multipart/alternative body, got %s", mediaType)
+	}
+	alternative := multipart.NewReader(body, params["boundary"])
+	for _, expected := range []struct{ contentType, content string }{
+		{"text/plain; charset=utf-8", "Report"},
+		{"text/html; charset=utf-8", "<h1>Report</h1>"},
+	} {
+		part, err := alternative.NextPart()
+		if err != nil {
+			t.Fatalf("Missing %s part: %v", expected.contentType, err)
+		}
+		content, _ := io.ReadAll(part) // multipart decodes quoted-printable
+		if part.Header.Get("Content-Type") != expected.contentType || string(content) != expected.content {
+			t.Errorf("Expected %s %q, got %s %q", expected.contentType, expected.content, part.Header.Get("Content-Type"), content)
+		}
+	}
+
+	attachment, err := mixed.NextPart()
+	if err != nil {
+		t.Fatalf("Missing attachment: %v", err)
+	}
+	if attachment.FileName() != "myorg.json" {
+		t.Errorf("Expected attachment myorg.json, got %q", attachment.FileName())
+	}
+	encoded, _ := io.ReadAll(attachment)
+	decoded, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(string(encoded), "\r\n", ""))
+	if err != nil || string(decoded) != `{"target":"myorg"}` {
+		t.Errorf("Unexpected attachment content %q (%v)", decoded, err)
+	}
+}
+
+func TestLoadEmailConfig(t *testing.T) {
+	write := func(content string) string {
+		path := filepath.Join(t.TempDir(), "email.json")
+		os.WriteFile(path, []byte(content), 0644)
+		return path
+	}
+
+	t.Setenv("SMTP_PASSWORD", "from-env")
+	config, err := LoadEmailConfig(write(`{
+		"smtp": {"host": "smtp.example.com", "username": "bot"},
+		"from": "reports@example.com",
+		"recipients": [{"name": "API", "to": ["lead@example.com"], "repositories": ["myorg/api-*"]}]
+	}`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if config.SMTP.Port != 587 || config.SMTP.TLS != TLSStartTLS || config.SMTP.Password != "from-env" {
+		t.Errorf("Unexpected SMTP defaults: %+v", config.SMTP)
+	}
+	if config.Subject != defaultSubject {
+		t.Errorf("Expected default subject, got %q", config.Subject)
+	}
+
+	implicit, err := LoadEmailConfig(write(`{"smtp": {"host": "smtp.example.com", "tls": "implicit"}, "from": "a@example.com", "recipients": [{"to": ["b@example.com"]}]}`))
+	if err != nil || implicit.SMTP.Port != 465 {
+		t.Errorf("Expected port 465 for implicit TLS, got %+v (%v)", implicit, err)
+	}
+
+	invalid := map[string]string{
+		"missing host":  `{"from": "a@example.com", "recipients": [{"to": ["b@example.com"]}]}`,
+		"bad tls":       `{"smtp": {"host": "h", "tls": "ssl"}, "from": "a@example.com", "recipients": [{"to": ["b@example.com"]}]}`,
+		"bad from":      `{"smtp": {"host": "h"}, "from": "nobody", "recipients": [{"to": ["b@example.com"]}]}`,
+		"no recipients": `{"smtp": {"host": "h"}, "from": "a@example.com"}`,
+		"no addresses":  `{"smtp": {"host": "h"}, "from": "a@example.com", "recipients": [{"name": "API"}]}`,
+		"bad address":   `{"smtp": {"host": "h"}, "from": "a@example.com", "recipients": [{"to": ["b@"]}]}`,
+		"bad pattern":   `{"smtp": {"host": "h"}, "from": "a@example.com", "recipients": [{"to": ["b@example.com"], "repositories": ["myorg/[api"]}]}`,
+	}
+	for name, content := range invalid {
+		if _, err := LoadEmailConfig(write(content)); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestWriteBase64Lines(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeBase64(&buf, bytes.Repeat([]byte("x"), 100)); err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\r\n"), "\r\n") {
+		if len(line) > 76 {
+			t.Errorf("Expected lines of at most 76 characters, got %d", len(line))
+		}
+	}
+}
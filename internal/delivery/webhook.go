@@ -0,0 +1,132 @@
+// Package delivery sends rendered reports to external services.
+package delivery
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// SignatureHeader carries the HMAC-SHA256 of the request body when a secret is set,
+// formatted like GitHub's X-Hub-Signature-256: "sha256=<hex digest>"
+const SignatureHeader = "X-Ghreporting-Signature-256"
+
+// Longest wait honoured from a Retry-After header
+const maxRetryAfter = time.Minute
+
+// defaultClient bounds each attempt, so an endpoint that never answers cannot block delivery
+var defaultClient = &http.Client{Timeout: 30 * time.Second}
+
+// Webhook posts JSON payloads to a URL, retrying rate limited, failed and
+// unreachable requests with exponential backoff
+type Webhook struct {
+	URL      string
+	Headers  map[string]string // Extra request headers, e.g. Authorization
+	Secret   string            // Signs the body in SignatureHeader when set
+	Attempts int               // Total attempts including the first (default 3)
+	Backoff  time.Duration     // Wait before the first retry, doubled for each further retry (default 1s)
+	Client   *http.Client      // Default client with a 30s timeout per attempt
+}
+
+// Sign returns the SignatureHeader value for a body
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Send posts the body, returning the last error once every attempt has failed
+func (w *Webhook) Send(ctx context.Context, body []byte) error {
+	attempts := w.Attempts
+	if attempts < 1 {
+		attempts = 3
+	}
+	backoff := w.Backoff
+	if backoff <= 0 {
+		backoff = time.Second
+	}
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		var retryAfter time.Duration
+		retryAfter, err = w.send(ctx, body)
+		if err == nil {
+			return nil
+		}
+		if retryAfter < 0 || attempt >= attempts {
+			break
+		}
+
+		wait := max(backoff, retryAfter)
+		slog.Warn("Webhook delivery failed, retrying", "attempt", attempt, "retry_in", wait, "error", err)
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return fmt.Errorf("failed to deliver webhook: %w", context.Cause(ctx))
+		}
+		backoff *= 2
+	}
+	return fmt.Errorf("failed to deliver webhook: %w", err)
+}
+
+// send makes one attempt. A negative retryAfter marks errors that retrying cannot fix.
+func (w *Webhook) send(ctx context.Context, body []byte) (retryAfter time.Duration, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return -1, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "ghreporting")
+	for name, value := range w.Headers {
+		req.Header.Set(name, value)
+	}
+	if w.Secret != "" {
+		req.Header.Set(SignatureHeader, Sign(w.Secret, body))
+	}
+
+	client := w.Client
+	if client == nil {
+		client = defaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return -1, err
+		}
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		io.Copy(io.Discard, resp.Body)
+		return 0, nil
+	}
+
+	message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	err = fmt.Errorf("%s: %s", resp.Status, bytes.TrimSpace(message))
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return parseRetryAfter(resp.Header.Get("Retry-After")), err
+	case resp.StatusCode >= 500:
+		return 0, err
+	default:
+		return -1, err
+	}
+}
+
+// parseRetryAfter reads a Retry-After header given in seconds
+func parseRetryAfter(value string) time.Duration {
+	seconds, err := strconv.Atoi(value)
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return min(time.Duration(seconds)*time.Second, maxRetryAfter)
+}
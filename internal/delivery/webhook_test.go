@@ -0,0 +1,126 @@
+package delivery
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSendSignsAndSetsHeaders(t *testing.T) {
+	body := []byte(`{"target":"myorg"}`)
+	var received atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received.Store(true)
+		if r.Method != http.MethodPost {
+			t.Errorf("Expected POST, got %s", r.Method)
+		}
+		if r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("Expected JSON content type, got %q", r.Header.Get("Content-Type"))
+		}
+		if r.Header.Get("Authorization") != "Bearer abc" {
+			t.Errorf("Expected custom Authorization header, got %q", r.Header.Get("Authorization"))
+		}
+		data, _ := io.ReadAll(r.Body)
+		if string(data) != string(body) {
+			t.Errorf("Expected body %s, got %s", body, data)
+		}
+		if r.Header.Get(SignatureHeader) != Sign("s3cret", data) {
+			t.Errorf("Signature does not match body: %q", r.Header.Get(SignatureHeader))
+		}
+	}))
+	defer server.Close()
+
+	webhook := &Webhook{URL: server.URL, Headers: map[string]string{"Authorization": "Bearer abc"}, Secret: "s3cret"}
+	if err := webhook.Send(context.Background(), body); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !received.Load() {
+		t.Error("Expected the webhook to be called")
+	}
+}
+
+func TestSign(t *testing.T) {
+	// Known HMAC-SHA256 test vector (RFC 4231 test case 2)
+	expected := "sha256=5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843"
+	if got := Sign("Jefe", []byte("what do ya want for nothing?")); got != expected {
+		t.Errorf("Expected %s, got %s", expected, got)
+	}
+}
+
+func TestSendRetries(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []int
+		attempts int
+		calls    int32
+		wantErr  bool
+	}{
+		{"success", []int{200}, 3, 1, false},
+		{"server error then success", []int{502, 503, 200}, 3, 3, false},
+		{"rate limited then success", []int{429, 204}, 3, 2, false},
+		{"attempts exhausted", []int{500, 500, 500, 500}, 3, 3, true},
+		{"client error is not retried", []int{400, 200}, 3, 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := calls.Add(1)
+				status := tt.statuses[min(int(n), len(tt.statuses))-1]
+				if status == http.StatusTooManyRequests {
+					w.Header().Set("Retry-After", "0")
+				}
+				w.WriteHeader(status)
+			}))
+			defer server.Close()
+
+			webhook := &Webhook{URL: server.URL, Attempts: tt.attempts, Backoff: time.Millisecond}
+			err := webhook.Send(context.Background(), []byte("{}"))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if calls.Load() != tt.calls {
+				t.Errorf("Expected %d calls, got %d", tt.calls, calls.Load())
+			}
+		})
+	}
+}
+
+func TestSendStopsWhenCancelled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	webhook := &Webhook{URL: server.URL, Attempts: 10, Backoff: time.Hour}
+	start := time.Now()
+	if err := webhook.Send(ctx, []byte("{}")); err == nil {
+		t.Error("Expected error when cancelled")
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Errorf("Expected Send to return when cancelled, took %v", time.Since(start))
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := map[string]time.Duration{
+		"":     0,
+		"5":    5 * time.Second,
+		"-1":   0,
+		"soon": 0,
+		"3600": maxRetryAfter,
+	}
+	for value, expected := range tests {
+		if got := parseRetryAfter(value); got != expected {
+			t.Errorf("parseRetryAfter(%q): expected %v, got %v", value, expected, got)
+		}
+	}
+}
@@ -20,14 +20,24 @@ type Branch struct {
 
 // Commit represents a commit with change statistics
 type Commit struct {
-	SHA       string    `json:"sha"`
-	Message   string    `json:"message"`
-	Author    Author    `json:"author"`
-	Date      time.Time `json:"date"`
-	Stats     CommitStats `json:"stats"`
+	SHA         string       `json:"sha"`
+	Message     string       `json:"message"`
+	Author      Author       `json:"author"`
+	Date        time.Time    `json:"date"` // Author date
+	Committer   Author       `json:"committer"`
+	CommitDate  time.Time    `json:"commit_date"`
+	Stats       CommitStats  `json:"stats"`
+	PullRequest *PullRequest `json:"pull_request,omitempty"`
 }
 
-// Author represents a commit author
+// PullRequest references the pull request a commit was merged through
+type PullRequest struct {
+	Number int    `json:"number"`
+	URL    string `json:"url"`
+	Author Author `json:"author"`
+}
+
+// Author represents a commit author or committer
 type Author struct {
 	Name  string `json:"name"`
 	Email string `json:"email"`
@@ -43,10 +53,31 @@ type CommitStats struct {
 
 // Report represents the final generated report
 type Report struct {
-	Target      string                    `json:"target"`
-	Period      Period                    `json:"period"`
-	Repositories []Repository             `json:"repositories"`
-	Summary     map[string]ContributorStats `json:"summary"`
+	Target       string                      `json:"target"`
+	Period       Period                      `json:"period"`
+	Repositories []Repository                `json:"repositories"`
+	Summary      map[string]ContributorStats `json:"summary"`
+	Incomplete   *Incomplete                 `json:"incomplete,omitempty"` // Set when generation was interrupted
+	Diagnostics  []Diagnostic                `json:"diagnostics,omitempty"`
+	Teams        map[string]TeamStats        `json:"teams,omitempty"` // Set when team memberships are configured
+}
+
+// Diagnostic records data that could not be fetched and is missing from the report
+type Diagnostic struct {
+	Stage      string `json:"stage"` // repository, branch, commit, pull_request or team
+	Repository string `json:"repository"`
+	Team       string `json:"team,omitempty"` // Set for teams whose members could not be fetched
+	Branch     string `json:"branch,omitempty"`
+	SHA        string `json:"sha,omitempty"`
+	Category   string `json:"category"` // e.g. rate_limit, not_found, forbidden, server_error
+	Error      string `json:"error"`
+}
+
+// Incomplete records which repositories an interrupted report covers
+type Incomplete struct {
+	Reason               string   `json:"reason"`
+	FinishedRepositories []string `json:"finished_repositories"`
+	SkippedRepositories  []string `json:"skipped_repositories"`
 }
 
 // Period represents the time range for the report
@@ -66,6 +97,19 @@ type ContributorStats struct {
 	Repositories map[string]RepositoryStats `json:"repositories"`
 }
 
+// TeamStats aggregates statistics per team. A commit credited to several members
+// of the same team counts once for the team.
+type TeamStats struct {
+	Name           string                     `json:"name"`
+	Members        []string                   `json:"members"`      // Configured members: logins, emails or contributor keys
+	MemberCount    int                        `json:"member_count"` // Distinct people among Members
+	Contributors   []string                   `json:"contributors"` // Summary keys of members with contributions in the period
+	TotalCommits   int                        `json:"total_commits"`
+	TotalAdditions int                        `json:"total_additions"`
+	TotalDeletions int                        `json:"total_deletions"`
+	Repositories   map[string]RepositoryStats `json:"repositories"`
+}
+
 // RepositoryStats represents contributor stats per repository
 type RepositoryStats struct {
 	Commits   int `json:"commits"`
@@ -0,0 +1,179 @@
+package progress
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Display modes accepted by Start
+const (
+	ModeAuto = "auto" // Bar on a terminal, log lines otherwise
+	ModeBar  = "bar"
+	ModeLog  = "log"
+	ModeOff  = "off"
+)
+
+// Width of the bar itself, excluding the counters
+const barWidth = 30
+
+// ValidateMode checks that mode is a supported display mode
+func ValidateMode(mode string) error {
+	switch mode {
+	case ModeAuto, ModeBar, ModeLog, ModeOff:
+		return nil
+	}
+	return fmt.Errorf("unsupported progress mode: %s (expected auto, bar, log or off)", mode)
+}
+
+// IsTerminal reports whether f is an interactive terminal
+func IsTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// Start displays the tracker's progress until the returned stop function is called.
+// The bar mode redraws a single line on out and passes the bar to redirect, which
+// should send log output through it so log lines do not break the bar. The log mode
+// logs a structured line every interval.
+func Start(ctx context.Context, tracker *Tracker, mode string, out *os.File, interval time.Duration, redirect func(io.Writer)) (stop func()) {
+	if mode == ModeAuto {
+		mode = ModeLog
+		if IsTerminal(out) {
+			mode = ModeBar
+		}
+	}
+	if tracker == nil || mode == ModeOff {
+		return func() {}
+	}
+
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+
+	var bar *Bar
+	if mode == ModeBar {
+		bar = &Bar{out: out}
+		redirect(bar)
+		interval = 200 * time.Millisecond
+	}
+
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if bar != nil {
+					bar.Draw(tracker.Snapshot())
+				} else {
+					slog.Info("Progress", tracker.Snapshot().Attrs()...)
+				}
+			}
+		}
+	}()
+
+	return func() {
+		cancel()
+		<-done
+		if bar != nil {
+			bar.Draw(tracker.Snapshot())
+			bar.Finish()
+			redirect(out)
+		}
+	}
+}
+
+// Bar draws a single-line progress bar on a terminal. Writes to it (log output) clear
+// the bar, print the text and redraw the bar below it.
+type Bar struct {
+	mu   sync.Mutex
+	out  io.Writer
+	line string
+}
+
+// Draw redraws the bar for the given snapshot
+func (b *Bar) Draw(s Snapshot) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.line = FormatBar(s)
+	fmt.Fprintf(b.out, "\r\033[K%s", b.line)
+}
+
+// Finish leaves the last drawn bar on its own line
+func (b *Bar) Finish() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.line != "" {
+		fmt.Fprintln(b.out)
+		b.line = ""
+	}
+}
+
+func (b *Bar) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.line != "" {
+		fmt.Fprint(b.out, "\r\033[K")
+	}
+	n, err := b.out.Write(p)
+	if b.line != "" {
+		fmt.Fprint(b.out, b.line)
+	}
+	return n, err
+}
+
+// FormatBar renders a snapshot as a progress bar line
+func FormatBar(s Snapshot) string {
+	filled := 0
+	if s.ReposTotal > 0 {
+		filled = barWidth * s.ReposDone / s.ReposTotal
+	}
+	bar := strings.Repeat("=", filled)
+	if filled < barWidth {
+		bar += ">" + strings.Repeat(" ", barWidth-filled-1)
+	}
+
+	line := fmt.Sprintf("[%s] %d/%d repos · %d commits · %d API calls", bar, s.ReposDone, s.ReposTotal, s.Commits, s.APICalls)
+	if s.RateRemaining >= 0 {
+		line += fmt.Sprintf(" · %d/%d rate limit left", s.RateRemaining, s.RateLimit)
+	}
+	if s.ETA > 0 {
+		line += " · ETA " + formatDuration(s.ETA)
+	}
+	return line
+}
+
+// Attrs returns the snapshot as structured log attributes
+func (s Snapshot) Attrs() []any {
+	attrs := []any{
+		"repos_done", s.ReposDone,
+		"repos_total", s.ReposTotal,
+		"commits", s.Commits,
+		"api_calls", s.APICalls,
+	}
+	if s.RateRemaining >= 0 {
+		attrs = append(attrs, "rate_remaining", s.RateRemaining, "rate_limit", s.RateLimit)
+	}
+	attrs = append(attrs, "elapsed", s.Elapsed.Round(time.Second))
+	if s.ETA > 0 {
+		attrs = append(attrs, "eta", s.ETA.Round(time.Second))
+	}
+	return attrs
+}
+
+// formatDuration rounds to whole seconds, e.g. "4m12s"
+func formatDuration(d time.Duration) string {
+	return d.Round(time.Second).String()
+}
@@ -0,0 +1,83 @@
+// Package progress tracks and displays the progress of report generation.
+package progress
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// RateSource reports API usage; it is implemented by client.GitHubClient
+type RateSource interface {
+	Requests() int64
+	RateRemaining() (remaining, limit int64)
+}
+
+// Tracker counts the work done while generating a report. It is safe for concurrent
+// use, and a nil Tracker ignores all updates.
+type Tracker struct {
+	start      time.Time
+	rate       RateSource
+	reposTotal atomic.Int64
+	reposDone  atomic.Int64
+	commits    atomic.Int64
+}
+
+// Snapshot is the state of a Tracker at one point in time
+type Snapshot struct {
+	ReposDone     int
+	ReposTotal    int
+	Commits       int
+	APICalls      int64
+	RateRemaining int64 // -1 when not known yet
+	RateLimit     int64
+	Elapsed       time.Duration
+	ETA           time.Duration // 0 until the first repository is done
+}
+
+// NewTracker creates a tracker reading API usage from rate, which may be nil
+func NewTracker(rate RateSource) *Tracker {
+	return &Tracker{start: time.Now(), rate: rate}
+}
+
+// SetRepositories records the number of repositories that will be processed
+func (t *Tracker) SetRepositories(total int) {
+	if t != nil {
+		t.reposTotal.Store(int64(total))
+	}
+}
+
+// RepositoryDone records that a repository was processed, successfully or not
+func (t *Tracker) RepositoryDone() {
+	if t != nil {
+		t.reposDone.Add(1)
+	}
+}
+
+// AddCommits records fetched commits
+func (t *Tracker) AddCommits(n int) {
+	if t != nil {
+		t.commits.Add(int64(n))
+	}
+}
+
+// Snapshot returns the current progress
+func (t *Tracker) Snapshot() Snapshot {
+	s := Snapshot{
+		ReposDone:     int(t.reposDone.Load()),
+		ReposTotal:    int(t.reposTotal.Load()),
+		Commits:       int(t.commits.Load()),
+		RateRemaining: -1,
+		Elapsed:       time.Since(t.start),
+	}
+	if t.rate != nil {
+		s.APICalls = t.rate.Requests()
+		if remaining, limit := t.rate.RateRemaining(); limit > 0 {
+			s.RateRemaining, s.RateLimit = remaining, limit
+		}
+	}
+	if s.ReposDone > 0 && s.ReposTotal > s.ReposDone {
+		perRepo := s.Elapsed / time.Duration(s.ReposDone)
+		s.ETA = perRepo * time.Duration(s.ReposTotal-s.ReposDone)
+	}
+	return s
+}
@@ -0,0 +1,98 @@
+package progress
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fakeRate struct{}
+
+func (fakeRate) Requests() int64                         { return 42 }
+func (fakeRate) RateRemaining() (remaining, limit int64) { return 4000, 5000 }
+
+func TestTrackerSnapshot(t *testing.T) {
+	tracker := NewTracker(fakeRate{})
+	tracker.start = time.Now().Add(-10 * time.Second)
+	tracker.SetRepositories(4)
+	tracker.RepositoryDone()
+	tracker.AddCommits(7)
+	tracker.AddCommits(3)
+
+	s := tracker.Snapshot()
+	if s.ReposDone != 1 || s.ReposTotal != 4 || s.Commits != 10 {
+		t.Errorf("Unexpected counters: %+v", s)
+	}
+	if s.APICalls != 42 || s.RateRemaining != 4000 || s.RateLimit != 5000 {
+		t.Errorf("Unexpected API usage: %+v", s)
+	}
+	if s.ETA < 29*time.Second || s.ETA > 31*time.Second {
+		t.Errorf("Expected an ETA of about 30s, got %s", s.ETA)
+	}
+
+	if NewTracker(nil).Snapshot().RateRemaining != -1 {
+		t.Error("Expected unknown rate limit without a rate source")
+	}
+
+	// A nil tracker ignores updates
+	var none *Tracker
+	none.SetRepositories(1)
+	none.RepositoryDone()
+	none.AddCommits(1)
+}
+
+func TestFormat(t *testing.T) {
+	s := Snapshot{ReposDone: 3, ReposTotal: 6, Commits: 120, APICalls: 300, RateRemaining: 4700, RateLimit: 5000, Elapsed: 90 * time.Second, ETA: 90 * time.Second}
+
+	bar := FormatBar(s)
+	for _, expected := range []string{"[" + strings.Repeat("=", 15) + ">", "3/6 repos", "120 commits", "300 API calls", "4700/5000 rate limit left", "ETA 1m30s"} {
+		if !strings.Contains(bar, expected) {
+			t.Errorf("Bar %q should contain %q", bar, expected)
+		}
+	}
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey || a.Key == slog.LevelKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+	logger.Info("Progress", s.Attrs()...)
+	expected := "msg=Progress repos_done=3 repos_total=6 commits=120 api_calls=300 rate_remaining=4700 rate_limit=5000 elapsed=1m30s eta=1m30s\n"
+	if buf.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buf.String())
+	}
+
+	s.RateRemaining, s.ETA = -1, 0
+	if attrs := s.Attrs(); len(attrs) != 10 {
+		t.Errorf("Unknown rate limit and ETA should be left out, got %v", attrs)
+	}
+}
+
+func TestBarRedrawsAfterLogLines(t *testing.T) {
+	var out bytes.Buffer
+	bar := &Bar{out: &out}
+	bar.Draw(Snapshot{ReposDone: 1, ReposTotal: 2, RateRemaining: -1})
+	bar.Write([]byte("log line\n"))
+
+	result := out.String()
+	if !strings.Contains(result, "\r\033[Klog line\n[") {
+		t.Errorf("Expected the bar to be cleared before and redrawn after a log line, got %q", result)
+	}
+}
+
+func TestValidateMode(t *testing.T) {
+	for _, mode := range []string{ModeAuto, ModeBar, ModeLog, ModeOff} {
+		if err := ValidateMode(mode); err != nil {
+			t.Errorf("Unexpected error for %s: %v", mode, err)
+		}
+	}
+	if err := ValidateMode("fancy"); err == nil {
+		t.Error("Expected error for unknown mode")
+	}
+}
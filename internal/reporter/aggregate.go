@@ -0,0 +1,118 @@
+package reporter
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"ghreporting/internal/models"
+)
+
+// contributorOrders are the supported contributor orderings, largest first except for name
+var contributorOrders = map[string]func(a, b models.ContributorStats) bool{
+	"changes": func(a, b models.ContributorStats) bool {
+		return a.TotalAdditions+a.TotalDeletions > b.TotalAdditions+b.TotalDeletions
+	},
+	"commits":   func(a, b models.ContributorStats) bool { return a.TotalCommits > b.TotalCommits },
+	"additions": func(a, b models.ContributorStats) bool { return a.TotalAdditions > b.TotalAdditions },
+	"deletions": func(a, b models.ContributorStats) bool { return a.TotalDeletions > b.TotalDeletions },
+	"name": func(a, b models.ContributorStats) bool {
+		return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+	},
+}
+
+// ValidateSortOrder checks that contributors can be sorted by the given field
+func ValidateSortOrder(order string) error {
+	if _, ok := contributorOrders[order]; !ok {
+		return fmt.Errorf("unsupported sort order: %s (expected changes, commits, additions, deletions or name)", order)
+	}
+	return nil
+}
+
+// SetSortOrder configures how contributors are ordered in every output format
+func (r *Reporter) SetSortOrder(order string) {
+	r.sortOrder = order
+}
+
+// sortedContributors returns the summary keys in the configured order, by total changes by default
+func (r *Reporter) sortedContributors(report *models.Report) []string {
+	less, ok := contributorOrders[r.sortOrder]
+	if !ok {
+		less = contributorOrders["changes"]
+	}
+
+	var contributors []string
+	for contributor := range report.Summary {
+		contributors = append(contributors, contributor)
+	}
+	sort.Slice(contributors, func(i, j int) bool {
+		a := report.Summary[contributors[i]]
+		b := report.Summary[contributors[j]]
+		if less(a, b) != less(b, a) {
+			return less(a, b)
+		}
+		return contributors[i] < contributors[j]
+	})
+	return contributors
+}
+
+// sortedRepositories returns the repository names of a contributor ordered by changes
+func sortedRepositories(stats models.ContributorStats) []string {
+	var repoNames []string
+	for repoName := range stats.Repositories {
+		repoNames = append(repoNames, repoName)
+	}
+	sort.Slice(repoNames, func(i, j int) bool {
+		a := stats.Repositories[repoNames[i]]
+		b := stats.Repositories[repoNames[j]]
+		if a.Additions+a.Deletions != b.Additions+b.Deletions {
+			return (a.Additions + a.Deletions) > (b.Additions + b.Deletions)
+		}
+		return repoNames[i] < repoNames[j]
+	})
+	return repoNames
+}
+
+// uniqueCommits returns the commits of a repository across all analyzed branches,
+// counting commits reachable from several branches only once
+func uniqueCommits(repo models.Repository) []models.Commit {
+	var commits []models.Commit
+	seen := make(map[string]bool)
+	for _, branch := range repo.Branches {
+		for _, commit := range branch.Commits {
+			if seen[commit.SHA] {
+				continue
+			}
+			seen[commit.SHA] = true
+			commits = append(commits, commit)
+		}
+	}
+	return commits
+}
+
+// repositoryTotals sums the unique commits of a repository
+func repositoryTotals(repo models.Repository) models.RepositoryStats {
+	var totals models.RepositoryStats
+	for _, commit := range uniqueCommits(repo) {
+		totals.Commits++
+		totals.Additions += commit.Stats.Additions
+		totals.Deletions += commit.Stats.Deletions
+	}
+	return totals
+}
+
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error { return nil }
+
+// openOutput returns the output file, or stdout when no file (or "-") is given
+func openOutput(outputFile string) (io.WriteCloser, error) {
+	if outputFile == "" || outputFile == "-" {
+		return nopCloser{os.Stdout}, nil
+	}
+	return os.Create(outputFile)
+}
@@ -0,0 +1,121 @@
+package reporter
+
+import (
+	"bufio"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"ghreporting/internal/models"
+)
+
+// CoAuthorSplit controls how line changes of a co-authored commit are credited
+type CoAuthorSplit string
+
+const (
+	// CoAuthorSplitAuthor credits all lines to the commit author; co-authors only get the commit
+	CoAuthorSplitAuthor CoAuthorSplit = "author"
+	// CoAuthorSplitEven divides lines evenly between the author and all co-authors
+	CoAuthorSplitEven CoAuthorSplit = "even"
+	// CoAuthorSplitFull credits every author and co-author with all lines of the commit
+	CoAuthorSplitFull CoAuthorSplit = "full"
+)
+
+// ParseCoAuthorSplit validates a split strategy name
+func ParseCoAuthorSplit(value string) (CoAuthorSplit, error) {
+	switch split := CoAuthorSplit(strings.ToLower(value)); split {
+	case CoAuthorSplitAuthor, CoAuthorSplitEven, CoAuthorSplitFull:
+		return split, nil
+	default:
+		return "", fmt.Errorf("unsupported co-author split strategy: %s", value)
+	}
+}
+
+var (
+	coAuthorTrailer = regexp.MustCompile(`(?i)^co-authored-by:\s*(.*?)\s*<([^>]+)>\s*$`)
+	noreplyEmail    = regexp.MustCompile(`(?i)^(?:\d+\+)?([a-z0-9](?:[a-z0-9-]*[a-z0-9])?)@users\.noreply\.github\.com$`)
+)
+
+// parseCoAuthors extracts the authors listed in Co-authored-by trailers of a commit message.
+// The GitHub login is derived from noreply addresses so co-authors match their own commits.
+func parseCoAuthors(message string) []models.Author {
+	var coAuthors []models.Author
+	seen := make(map[string]bool)
+
+	scanner := bufio.NewScanner(strings.NewReader(message))
+	for scanner.Scan() {
+		match := coAuthorTrailer.FindStringSubmatch(strings.TrimSpace(scanner.Text()))
+		if match == nil {
+			continue
+		}
+
+		author := models.Author{
+			Name:  match[1],
+			Email: strings.TrimSpace(match[2]),
+		}
+		if login := noreplyEmail.FindStringSubmatch(author.Email); login != nil {
+			author.Login = login[1]
+		}
+		if author.Name == "" {
+			author.Name = author.Email
+		}
+
+		key := strings.ToLower(author.Email)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		coAuthors = append(coAuthors, author)
+	}
+
+	return coAuthors
+}
+
+// commitCredit is the share of a commit attributed to a single contributor
+type commitCredit struct {
+	author    models.Author
+	additions int
+	deletions int
+}
+
+// commitCredits returns every contributor credited for a commit according to the
+// attribution and co-author settings. The attributed identity is always the first
+// entry; co-authors are only credited when attributing commits to their author.
+func (r *Reporter) commitCredits(commit models.Commit) []commitCredit {
+	identity := r.commitIdentity(commit)
+	credits := []commitCredit{{
+		author:    identity,
+		additions: commit.Stats.Additions,
+		deletions: commit.Stats.Deletions,
+	}}
+	if !r.coAuthors || r.attribution == IdentityCommitter {
+		return credits
+	}
+
+	authorKey := r.getAuthorKey(identity)
+	for _, coAuthor := range parseCoAuthors(commit.Message) {
+		if r.getAuthorKey(coAuthor) == authorKey || strings.EqualFold(coAuthor.Email, identity.Email) {
+			continue
+		}
+		credits = append(credits, commitCredit{author: coAuthor})
+	}
+
+	switch r.coAuthorSplit {
+	case CoAuthorSplitFull:
+		for i := range credits {
+			credits[i].additions = commit.Stats.Additions
+			credits[i].deletions = commit.Stats.Deletions
+		}
+	case CoAuthorSplitEven:
+		n := len(credits)
+		for i := range credits {
+			credits[i].additions = commit.Stats.Additions / n
+			credits[i].deletions = commit.Stats.Deletions / n
+		}
+		// Keep totals exact by giving the remainder to the commit author
+		credits[0].additions += commit.Stats.Additions % n
+		credits[0].deletions += commit.Stats.Deletions % n
+	}
+
+	return credits
+}
@@ -0,0 +1,90 @@
+package reporter
+
+import (
+	"testing"
+
+	"ghreporting/internal/models"
+)
+
+func TestParseCoAuthors(t *testing.T) {
+	message := `Add pairing support
+
+Some details about the change.
+
+Co-authored-by: Jane Doe <12345+janedoe@users.noreply.github.com>
+co-authored-by: Bob Smith <bob@example.com>
+Co-authored-by: Bob Again <bob@example.com>`
+
+	coAuthors := parseCoAuthors(message)
+
+	if len(coAuthors) != 2 {
+		t.Fatalf("Expected 2 co-authors, got %d", len(coAuthors))
+	}
+	if coAuthors[0].Name != "Jane Doe" || coAuthors[0].Login != "janedoe" {
+		t.Errorf("Unexpected first co-author: %+v", coAuthors[0])
+	}
+	if coAuthors[1].Email != "bob@example.com" || coAuthors[1].Login != "" {
+		t.Errorf("Unexpected second co-author: %+v", coAuthors[1])
+	}
+}
+
+func TestGenerateSummaryCoAuthors(t *testing.T) {
+	repos := []models.Repository{
+		{
+			FullName: "owner/repo1",
+			Branches: []models.Branch{
+				{
+					Name: "main",
+					Commits: []models.Commit{
+						{
+							SHA:     "abc123",
+							Message: "Pair on parser\n\nCo-authored-by: Jane Doe <jane@example.com>",
+							Author:  models.Author{Name: "John Doe", Email: "john@example.com", Login: "johndoe"},
+							Stats:   models.CommitStats{Additions: 11, Deletions: 4, Total: 15},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		coAuthors     bool
+		split         CoAuthorSplit
+		contributors  int
+		johnAdditions int
+		janeAdditions int
+		janeDeletions int
+	}{
+		{name: "disabled", coAuthors: false, split: CoAuthorSplitAuthor, contributors: 1, johnAdditions: 11},
+		{name: "author split", coAuthors: true, split: CoAuthorSplitAuthor, contributors: 2, johnAdditions: 11},
+		{name: "even split", coAuthors: true, split: CoAuthorSplitEven, contributors: 2, johnAdditions: 6, janeAdditions: 5, janeDeletions: 2},
+		{name: "full split", coAuthors: true, split: CoAuthorSplitFull, contributors: 2, johnAdditions: 11, janeAdditions: 11, janeDeletions: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Reporter{coAuthors: tt.coAuthors, coAuthorSplit: tt.split}
+			summary := r.generateSummary(repos)
+
+			if len(summary) != tt.contributors {
+				t.Fatalf("Expected %d contributors, got %d", tt.contributors, len(summary))
+			}
+			if summary["johndoe"].TotalAdditions != tt.johnAdditions {
+				t.Errorf("Expected %d additions for johndoe, got %d", tt.johnAdditions, summary["johndoe"].TotalAdditions)
+			}
+			if !tt.coAuthors {
+				return
+			}
+
+			jane := summary["jane@example.com"]
+			if jane.TotalCommits != 1 {
+				t.Errorf("Expected co-author to be credited with 1 commit, got %d", jane.TotalCommits)
+			}
+			if jane.TotalAdditions != tt.janeAdditions || jane.TotalDeletions != tt.janeDeletions {
+				t.Errorf("Expected co-author +%d/-%d, got +%d/-%d", tt.janeAdditions, tt.janeDeletions, jane.TotalAdditions, jane.TotalDeletions)
+			}
+		})
+	}
+}
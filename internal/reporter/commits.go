@@ -0,0 +1,179 @@
+package reporter
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"ghreporting/internal/models"
+)
+
+// CommitRecord is a flattened commit row used by the commit-level exports
+type CommitRecord struct {
+	Repository  string    `json:"repository"`
+	Branch      string    `json:"branch"`
+	SHA         string    `json:"sha"`
+	AuthorName  string    `json:"author_name"`
+	AuthorEmail string    `json:"author_email"`
+	AuthorLogin string    `json:"author_login"`
+	Date        time.Time `json:"date"`
+	Additions   int       `json:"additions"`
+	Deletions   int       `json:"deletions"`
+	Subject     string    `json:"subject"`
+}
+
+var commitCSVHeader = []string{"Repository", "Branch", "SHA", "Author", "Login", "Email", "Date", "Additions", "Deletions", "Subject"}
+
+// NewCommitRecord flattens a commit of a repository branch
+func NewCommitRecord(repo, branch string, commit models.Commit) CommitRecord {
+	subject, _, _ := strings.Cut(commit.Message, "\n")
+	return CommitRecord{
+		Repository:  repo,
+		Branch:      branch,
+		SHA:         commit.SHA,
+		AuthorName:  commit.Author.Name,
+		AuthorEmail: commit.Author.Email,
+		AuthorLogin: commit.Author.Login,
+		Date:        commit.Date,
+		Additions:   commit.Stats.Additions,
+		Deletions:   commit.Stats.Deletions,
+		Subject:     strings.TrimSpace(subject),
+	}
+}
+
+// CommitWriter writes commit records as CSV or newline-delimited JSON. It is safe
+// for concurrent use; the first write error is kept and returned by Close.
+type CommitWriter struct {
+	mu     sync.Mutex
+	out    io.WriteCloser
+	buf    *bufio.Writer
+	csv    *csv.Writer
+	ndjson *json.Encoder
+	err    error
+}
+
+// OpenCommitWriter creates a commit writer for outputFile ("" or "-" for stdout) in
+// the given format: csv or ndjson
+func OpenCommitWriter(outputFile, format string) (*CommitWriter, error) {
+	if format != "csv" && format != "ndjson" {
+		return nil, fmt.Errorf("unsupported commit export format: %s", format)
+	}
+	out, err := openOutput(outputFile)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &CommitWriter{out: out, buf: bufio.NewWriter(out)}
+	if format == "ndjson" {
+		w.ndjson = json.NewEncoder(w.buf)
+		return w, nil
+	}
+
+	w.csv = csv.NewWriter(w.buf)
+	w.err = w.csv.Write(commitCSVHeader)
+	return w, nil
+}
+
+// WriteCommit writes a single commit record
+func (w *CommitWriter) WriteCommit(record CommitRecord) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.err != nil {
+		return w.err
+	}
+
+	if w.ndjson != nil {
+		w.err = w.ndjson.Encode(record)
+		return w.err
+	}
+
+	w.err = w.csv.Write([]string{
+		record.Repository,
+		record.Branch,
+		record.SHA,
+		record.AuthorName,
+		record.AuthorLogin,
+		record.AuthorEmail,
+		record.Date.Format(time.RFC3339),
+		strconv.Itoa(record.Additions),
+		strconv.Itoa(record.Deletions),
+		record.Subject,
+	})
+	return w.err
+}
+
+// WriteBranch writes every commit of a repository branch and flushes them to the output
+func (w *CommitWriter) WriteBranch(repo string, branch models.Branch) error {
+	for _, commit := range branch.Commits {
+		if err := w.WriteCommit(NewCommitRecord(repo, branch.Name, commit)); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
+
+// Flush writes buffered records to the output
+func (w *CommitWriter) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.flush()
+	return w.err
+}
+
+func (w *CommitWriter) flush() {
+	if w.csv != nil {
+		w.csv.Flush()
+		if w.err == nil {
+			w.err = w.csv.Error()
+		}
+	}
+	if err := w.buf.Flush(); w.err == nil {
+		w.err = err
+	}
+}
+
+// Close flushes buffered records and closes the output
+func (w *CommitWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.flush()
+	if err := w.out.Close(); w.err == nil {
+		w.err = err
+	}
+	return w.err
+}
+
+// SetCommitStream configures a writer that receives the commits of every repository as
+// soon as it has been fetched, instead of only after the whole report has been
+// generated. Repositories left out of the report are left out of the stream too. The
+// report still keeps every commit for its summary and outputs.
+func (r *Reporter) SetCommitStream(w *CommitWriter) {
+	r.commitStream = w
+}
+
+func (r *Reporter) outputCommits(report *models.Report, outputFile, format string) error {
+	w, err := OpenCommitWriter(outputFile, format)
+	if err != nil {
+		return err
+	}
+
+	for _, repo := range report.Repositories {
+		for _, branch := range repo.Branches {
+			if err := w.WriteBranch(repo.FullName, branch); err != nil {
+				w.Close()
+				return err
+			}
+		}
+	}
+
+	return w.Close()
+}
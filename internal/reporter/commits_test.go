@@ -0,0 +1,86 @@
+package reporter
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestOutputCommitsCSV(t *testing.T) {
+	r := &Reporter{}
+	outputFile := filepath.Join(t.TempDir(), "commits.csv")
+
+	if err := r.OutputReport(testReport(), outputFile, "commits-csv"); err != nil {
+		t.Fatalf("Failed to write commit CSV: %v", err)
+	}
+
+	file, err := os.Open(outputFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	records, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to parse commit CSV: %v", err)
+	}
+
+	if len(records) != 3 {
+		t.Fatalf("Expected header and 2 commits, got %d rows", len(records))
+	}
+	expected := []string{"owner/repo1", "main", "abc123", "John Doe", "johndoe", "john@example.com", "2024-01-03T10:00:00Z", "10", "5", "Add feature"}
+	for i, value := range expected {
+		if records[1][i] != value {
+			t.Errorf("Column %s: expected %q, got %q", records[0][i], value, records[1][i])
+		}
+	}
+}
+
+func TestCommitWriterNDJSONConcurrent(t *testing.T) {
+	outputFile := filepath.Join(t.TempDir(), "commits.ndjson")
+	report := testReport()
+
+	w, err := OpenCommitWriter(outputFile, "ndjson")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := w.WriteBranch("owner/repo1", report.Repositories[0].Branches[0]); err != nil {
+				t.Errorf("Failed to write branch: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if err := w.Close(); err != nil {
+		t.Fatalf("Failed to close writer: %v", err)
+	}
+
+	file, err := os.Open(outputFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	lines := 0
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var record CommitRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("Invalid NDJSON line %q: %v", scanner.Text(), err)
+		}
+		lines++
+	}
+	if lines != 20 {
+		t.Errorf("Expected 20 records, got %d", lines)
+	}
+}
@@ -0,0 +1,121 @@
+package reporter
+
+import (
+	"sort"
+	"sync"
+
+	"ghreporting/internal/client"
+	"ghreporting/internal/models"
+)
+
+// Stages at which a run can skip data
+const (
+	StageRepository  = "repository"
+	StageBranch      = "branch"
+	StageCommit      = "commit"
+	StagePullRequest = "pull_request"
+	StageTeam        = "team"
+)
+
+// diagnosticLog collects the data a run had to skip; it is safe for concurrent use
+type diagnosticLog struct {
+	mu      sync.Mutex
+	entries []models.Diagnostic
+}
+
+func (d *diagnosticLog) add(stage, repo, branch, sha string, err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.entries = append(d.entries, models.Diagnostic{
+		Stage:      stage,
+		Repository: repo,
+		Branch:     branch,
+		SHA:        sha,
+		Category:   client.Categorize(err),
+		Error:      err.Error(),
+	})
+}
+
+// addTeam records a team whose members could not be fetched
+func (d *diagnosticLog) addTeam(team string, err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.entries = append(d.entries, models.Diagnostic{
+		Stage:    StageTeam,
+		Team:     team,
+		Category: client.Categorize(err),
+		Error:    err.Error(),
+	})
+}
+
+// report returns the collected diagnostics in a stable order, leaving out repositories
+// that were skipped as a whole because the run was interrupted
+func (d *diagnosticLog) report(skipped []string) []models.Diagnostic {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	skip := make(map[string]bool)
+	for _, repo := range skipped {
+		skip[repo] = true
+	}
+
+	var entries []models.Diagnostic
+	for _, entry := range d.entries {
+		if !skip[entry.Repository] {
+			entries = append(entries, entry)
+		}
+	}
+	sortDiagnostics(entries)
+	return entries
+}
+
+func sortDiagnostics(entries []models.Diagnostic) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.Repository != b.Repository {
+			return a.Repository < b.Repository
+		}
+		if a.Team != b.Team {
+			return a.Team < b.Team
+		}
+		if a.Branch != b.Branch {
+			return a.Branch < b.Branch
+		}
+		return a.SHA < b.SHA
+	})
+}
+
+// diagnosticLocation formats the team, or the repository, branch and commit, a
+// diagnostic refers to
+func diagnosticLocation(entry models.Diagnostic) string {
+	if entry.Team != "" {
+		return "team " + entry.Team
+	}
+	location := entry.Repository
+	if entry.Branch != "" {
+		location += "@" + entry.Branch
+	}
+	if entry.SHA != "" {
+		location += " " + entry.SHA
+	}
+	return location
+}
+
+// combineDiagnostics concatenates the diagnostics of several reports
+func combineDiagnostics(reports []*models.Report) []models.Diagnostic {
+	var entries []models.Diagnostic
+	for _, report := range reports {
+		entries = append(entries, report.Diagnostics...)
+	}
+	sortDiagnostics(entries)
+	return entries
+}
+
+// diagnosticCounts returns the number of diagnostics per category
+func diagnosticCounts(report *models.Report) map[string]int {
+	counts := make(map[string]int)
+	for _, entry := range report.Diagnostics {
+		counts[entry.Category]++
+	}
+	return counts
+}
@@ -0,0 +1,89 @@
+package reporter
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"ghreporting/internal/models"
+)
+
+func TestDiagnosticLog(t *testing.T) {
+	diagnostics := &diagnosticLog{}
+	diagnostics.add(StageCommit, "owner/repo2", "main", "bbb", errors.New("boom"))
+	diagnostics.add(StageBranch, "owner/repo1", "develop", "", errors.New("boom"))
+	diagnostics.add(StageRepository, "owner/repo3", "", "", context.Canceled)
+
+	entries := diagnostics.report([]string{"owner/repo3"})
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 diagnostics after dropping skipped repositories, got %d", len(entries))
+	}
+	if entries[0].Repository != "owner/repo1" || entries[0].Stage != StageBranch {
+		t.Errorf("Expected owner/repo1 branch diagnostic first, got %+v", entries[0])
+	}
+	if entries[1].Category != "other" || entries[1].SHA != "bbb" || entries[1].Error != "boom" {
+		t.Errorf("Unexpected commit diagnostic: %+v", entries[1])
+	}
+}
+
+func diagnosticsReport() *models.Report {
+	report := testReport()
+	report.Diagnostics = []models.Diagnostic{
+		{Stage: StageCommit, Repository: "owner/repo1", Branch: "main", SHA: "fff999", Category: "server_error", Error: "502 Bad Gateway"},
+		{Stage: StageRepository, Repository: "owner/repo2", Category: "forbidden", Error: "403 Forbidden"},
+	}
+	return report
+}
+
+func TestDiagnosticsOutputs(t *testing.T) {
+	r := &Reporter{}
+	dir := t.TempDir()
+
+	for _, format := range []string{"text", "html", "markdown", "json"} {
+		outputFile := filepath.Join(dir, "report."+format)
+		if err := r.OutputReport(diagnosticsReport(), outputFile, format); err != nil {
+			t.Fatalf("Failed to write %s report: %v", format, err)
+		}
+		data, err := os.ReadFile(outputFile)
+		if err != nil {
+			t.Fatalf("Failed to read %s report: %v", format, err)
+		}
+		for _, expected := range []string{"fff999", "server_error", "owner/repo2"} {
+			if !strings.Contains(string(data), expected) {
+				t.Errorf("%s output should contain %q", format, expected)
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := writeOpenMetrics(&buf, diagnosticsReport()); err != nil {
+		t.Fatalf("Failed to write OpenMetrics: %v", err)
+	}
+	if !strings.Contains(buf.String(), `ghreporting_report_diagnostics{target="owner",category="forbidden"} 1`) {
+		t.Errorf("OpenMetrics output should count diagnostics by category:\n%s", buf.String())
+	}
+}
+
+func TestDiagnosticsSurviveFilterAndMerge(t *testing.T) {
+	r := &Reporter{}
+
+	filtered, err := r.FilterReport(diagnosticsReport(), ReportFilter{Repositories: []string{"owner/repo1"}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(filtered.Diagnostics) != 1 || filtered.Diagnostics[0].Repository != "owner/repo1" {
+		t.Errorf("Expected only owner/repo1 diagnostics, got %+v", filtered.Diagnostics)
+	}
+
+	merged, err := r.MergeReports([]*models.Report{diagnosticsReport(), testReport(), diagnosticsReport()})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(merged.Diagnostics) != 4 {
+		t.Errorf("Expected 4 diagnostics, got %d", len(merged.Diagnostics))
+	}
+}
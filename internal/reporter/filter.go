@@ -0,0 +1,147 @@
+package reporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"ghreporting/internal/models"
+)
+
+// ReportFilter narrows a report before it is rendered. Zero values keep everything.
+type ReportFilter struct {
+	Since        time.Time
+	Until        time.Time
+	Repositories []string // Glob patterns matched against full names, e.g. "myorg/api-*"
+	Contributors []string // Contributor keys, logins, emails or names
+}
+
+// LoadReport reads a report written by the json output format ("-" reads stdin)
+func LoadReport(path string) (*models.Report, error) {
+	var input io.Reader = os.Stdin
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		input = file
+	}
+
+	var report models.Report
+	if err := json.NewDecoder(input).Decode(&report); err != nil {
+		return nil, fmt.Errorf("failed to decode report %s: %w", path, err)
+	}
+	if report.Summary == nil {
+		report.Summary = make(map[string]models.ContributorStats)
+	}
+	return &report, nil
+}
+
+// FilterReport returns a copy of the report restricted by filter. The summary is
+// recomputed from the remaining commits using the reporter's attribution, co-author
+// and period settings, so a saved report can be re-rendered with different options.
+func (r *Reporter) FilterReport(report *models.Report, filter ReportFilter) (*models.Report, error) {
+	for _, pattern := range filter.Repositories {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid repository pattern %q: %w", pattern, err)
+		}
+	}
+
+	period := report.Period
+	if !filter.Since.IsZero() && filter.Since.After(period.Since) {
+		period.Since = filter.Since
+	}
+	if !filter.Until.IsZero() && filter.Until.Before(period.Until) {
+		period.Until = filter.Until
+	}
+	if period.Until.Before(period.Since) {
+		return nil, fmt.Errorf("filter period %s to %s does not overlap the report period",
+			period.Since.Format("2006-01-02"), period.Until.Format("2006-01-02"))
+	}
+
+	contributors := make(map[string]bool)
+	for _, contributor := range filter.Contributors {
+		contributors[strings.ToLower(contributor)] = true
+	}
+	matchesContributor := func(author models.Author) bool {
+		return contributors[strings.ToLower(r.getAuthorKey(author))] ||
+			(author.Login != "" && contributors[strings.ToLower(author.Login)]) ||
+			(author.Email != "" && contributors[strings.ToLower(author.Email)]) ||
+			(author.Name != "" && contributors[strings.ToLower(author.Name)])
+	}
+
+	filtered := *report
+	filtered.Period = period
+	filtered.Repositories = nil
+
+	for _, repo := range report.Repositories {
+		if !matchesAny(filter.Repositories, repo.FullName) {
+			continue
+		}
+
+		repoCopy := repo
+		repoCopy.Branches = nil
+		for _, branch := range repo.Branches {
+			branchCopy := branch
+			branchCopy.Commits = nil
+			for _, commit := range branch.Commits {
+				if !r.inPeriod(commit, period.Since, period.Until) {
+					continue
+				}
+				if len(contributors) > 0 && !r.creditsAny(commit, matchesContributor) {
+					continue
+				}
+				branchCopy.Commits = append(branchCopy.Commits, commit)
+			}
+			repoCopy.Branches = append(repoCopy.Branches, branchCopy)
+		}
+		filtered.Repositories = append(filtered.Repositories, repoCopy)
+	}
+
+	filtered.Diagnostics = nil
+	for _, entry := range report.Diagnostics {
+		if matchesAny(filter.Repositories, entry.Repository) {
+			filtered.Diagnostics = append(filtered.Diagnostics, entry)
+		}
+	}
+
+	filtered.Summary = r.generateSummary(filtered.Repositories)
+	filtered.Teams = r.generateTeams(filtered.Repositories, r.teamMembers(report))
+	if len(contributors) > 0 {
+		for key, stats := range filtered.Summary {
+			if !matchesContributor(models.Author{Name: stats.Name, Email: stats.Email, Login: stats.Login}) && !contributors[strings.ToLower(key)] {
+				delete(filtered.Summary, key)
+			}
+		}
+	}
+
+	return &filtered, nil
+}
+
+// creditsAny reports whether any contributor credited for the commit satisfies match
+func (r *Reporter) creditsAny(commit models.Commit, match func(models.Author) bool) bool {
+	for _, credit := range r.commitCredits(commit) {
+		if match(credit.author) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesAny reports whether name matches one of the glob patterns; no patterns match everything
+func matchesAny(patterns []string, name string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
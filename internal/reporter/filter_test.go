@@ -0,0 +1,103 @@
+package reporter
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadReportRoundTrip(t *testing.T) {
+	r := &Reporter{}
+	path := filepath.Join(t.TempDir(), "report.json")
+	if err := r.OutputReport(testReport(), path, "json"); err != nil {
+		t.Fatalf("Failed to write JSON report: %v", err)
+	}
+
+	report, err := LoadReport(path)
+	if err != nil {
+		t.Fatalf("Failed to load report: %v", err)
+	}
+	if report.Target != "owner" {
+		t.Errorf("Expected target owner, got %s", report.Target)
+	}
+	if len(report.Repositories) != 1 || len(report.Repositories[0].Branches[0].Commits) != 2 {
+		t.Errorf("Expected 1 repository with 2 commits, got %+v", report.Repositories)
+	}
+	if report.Summary["johndoe"].TotalAdditions != 10 {
+		t.Errorf("Expected 10 additions for johndoe, got %d", report.Summary["johndoe"].TotalAdditions)
+	}
+
+	if _, err := LoadReport(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("Expected error for missing file")
+	}
+
+	invalid := filepath.Join(t.TempDir(), "invalid.json")
+	os.WriteFile(invalid, []byte("not json"), 0644)
+	if _, err := LoadReport(invalid); err == nil {
+		t.Error("Expected error for invalid JSON")
+	}
+}
+
+func TestFilterReport(t *testing.T) {
+	r := &Reporter{coAuthors: true}
+	original := testReport()
+
+	// No filter keeps everything
+	report, err := r.FilterReport(original, ReportFilter{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(report.Summary) != 2 {
+		t.Errorf("Expected 2 contributors, got %d", len(report.Summary))
+	}
+
+	// Period filter narrows commits and the report period
+	report, err = r.FilterReport(original, ReportFilter{Since: time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, exists := report.Summary["johndoe"]; exists {
+		t.Error("Expected johndoe to be filtered out by period")
+	}
+	if !report.Period.Since.Equal(time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected period to start 2024-01-10, got %s", report.Period.Since)
+	}
+	if len(original.Repositories[0].Branches[0].Commits) != 2 {
+		t.Error("Filtering should not modify the original report")
+	}
+
+	// Contributor filter
+	report, err = r.FilterReport(original, ReportFilter{Contributors: []string{"JaneSmith"}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(report.Summary) != 1 || report.Summary["janesmith"].TotalCommits != 1 {
+		t.Errorf("Expected only janesmith, got %v", report.Summary)
+	}
+
+	report, err = r.FilterReport(original, ReportFilter{Contributors: []string{"john doe"}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(report.Summary) != 1 || report.Summary["johndoe"].TotalCommits != 1 {
+		t.Errorf("Expected only johndoe matched by name, got %v", report.Summary)
+	}
+
+	// Repository filter
+	report, err = r.FilterReport(original, ReportFilter{Repositories: []string{"other/*"}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(report.Repositories) != 0 || len(report.Summary) != 0 {
+		t.Errorf("Expected no repositories, got %d", len(report.Repositories))
+	}
+
+	// Invalid inputs
+	if _, err := r.FilterReport(original, ReportFilter{Repositories: []string{"["}}); err == nil {
+		t.Error("Expected error for invalid pattern")
+	}
+	if _, err := r.FilterReport(original, ReportFilter{Since: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}); err == nil {
+		t.Error("Expected error for non-overlapping period")
+	}
+}
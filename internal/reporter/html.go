@@ -0,0 +1,447 @@
+package reporter
+
+import (
+	"fmt"
+	"html/template"
+	"sort"
+	"strings"
+	"time"
+
+	"ghreporting/internal/models"
+)
+
+// Chart geometry in SVG user units
+const (
+	chartWidth     = 720
+	chartBarHeight = 18
+	chartLabelSpan = 220
+	timelineHeight = 120
+	maxChartBars   = 15
+)
+
+type htmlReport struct {
+	Report       *models.Report
+	Generated    string
+	Totals       models.RepositoryStats
+	Contributors []htmlContributor
+	Repositories []htmlRepository
+	Teams        []htmlTeam
+	Chart        htmlBarChart
+	Timeline     htmlTimeline
+	Incomplete   string // Warning shown for interrupted reports
+	Skipped      string
+}
+
+type htmlContributor struct {
+	Key          string
+	Name         string
+	Login        string
+	Email        string
+	Commits      int
+	Additions    int
+	Deletions    int
+	Repositories int
+}
+
+type htmlRepository struct {
+	FullName     string
+	URL          string
+	Branches     int
+	Totals       models.RepositoryStats
+	Contributors []htmlContributor
+	Timeline     htmlTimeline
+}
+
+type htmlTeam struct {
+	Name         string
+	Members      int
+	Active       int
+	Totals       models.RepositoryStats
+	Repositories []htmlTeamRepository
+}
+
+type htmlTeamRepository struct {
+	FullName string
+	models.RepositoryStats
+}
+
+type htmlBarChart struct {
+	Height int
+	Bars   []htmlBar
+}
+
+type htmlBar struct {
+	Label     string
+	Y         int
+	AddWidth  float64
+	DelX      float64
+	DelWidth  float64
+	Additions int
+	Deletions int
+}
+
+type htmlTimeline struct {
+	Unit    string
+	First   string
+	Last    string
+	Columns []htmlColumn
+}
+
+type htmlColumn struct {
+	Label   string
+	X       float64
+	Y       float64
+	Width   float64
+	Height  float64
+	Commits int
+}
+
+func (r *Reporter) outputHTML(report *models.Report, outputFile string) error {
+	output, err := openOutput(outputFile)
+	if err != nil {
+		return err
+	}
+	defer output.Close()
+
+	return htmlTemplate.Execute(output, r.buildHTMLReport(report))
+}
+
+func (r *Reporter) buildHTMLReport(report *models.Report) htmlReport {
+	view := htmlReport{
+		Report:    report,
+		Generated: time.Now().Format("2006-01-02 15:04 MST"),
+	}
+	if notice := incompleteNotice(report); notice != "" {
+		view.Incomplete = notice
+		view.Skipped = strings.Join(report.Incomplete.SkippedRepositories, ", ")
+	}
+
+	for _, key := range r.sortedContributors(report) {
+		stats := report.Summary[key]
+		view.Contributors = append(view.Contributors, htmlContributor{
+			Key:          key,
+			Name:         stats.Name,
+			Login:        stats.Login,
+			Email:        stats.Email,
+			Commits:      stats.TotalCommits,
+			Additions:    stats.TotalAdditions,
+			Deletions:    stats.TotalDeletions,
+			Repositories: len(stats.Repositories),
+		})
+	}
+	view.Chart = contributorChart(view.Contributors)
+
+	var allCommits []models.Commit
+	for _, repo := range report.Repositories {
+		commits := uniqueCommits(repo)
+		allCommits = append(allCommits, commits...)
+
+		totals := repositoryTotals(repo)
+		view.Totals.Commits += totals.Commits
+		view.Totals.Additions += totals.Additions
+		view.Totals.Deletions += totals.Deletions
+
+		htmlRepo := htmlRepository{
+			FullName: repo.FullName,
+			URL:      repo.URL,
+			Branches: len(repo.Branches),
+			Totals:   totals,
+			Timeline: r.activityTimeline(report.Period, commits),
+		}
+		for _, contributor := range view.Contributors {
+			repoStats, exists := report.Summary[contributor.Key].Repositories[repo.FullName]
+			if !exists {
+				continue
+			}
+			htmlRepo.Contributors = append(htmlRepo.Contributors, htmlContributor{
+				Name:      contributor.Name,
+				Login:     contributor.Login,
+				Commits:   repoStats.Commits,
+				Additions: repoStats.Additions,
+				Deletions: repoStats.Deletions,
+			})
+		}
+		view.Repositories = append(view.Repositories, htmlRepo)
+	}
+	sort.SliceStable(view.Repositories, func(i, j int) bool {
+		a, b := view.Repositories[i].Totals, view.Repositories[j].Totals
+		return (a.Additions + a.Deletions) > (b.Additions + b.Deletions)
+	})
+	view.Timeline = r.activityTimeline(report.Period, allCommits)
+
+	for _, name := range r.sortedTeams(report) {
+		team := report.Teams[name]
+		htmlTeam := htmlTeam{
+			Name:    team.Name,
+			Members: team.MemberCount,
+			Active:  len(team.Contributors),
+			Totals: models.RepositoryStats{
+				Commits:   team.TotalCommits,
+				Additions: team.TotalAdditions,
+				Deletions: team.TotalDeletions,
+			},
+		}
+		for _, repoName := range teamRepositories(team) {
+			htmlTeam.Repositories = append(htmlTeam.Repositories, htmlTeamRepository{repoName, team.Repositories[repoName]})
+		}
+		view.Teams = append(view.Teams, htmlTeam)
+	}
+
+	return view
+}
+
+// contributorChart lays out a horizontal stacked bar chart of the top contributors
+func contributorChart(contributors []htmlContributor) htmlBarChart {
+	if len(contributors) > maxChartBars {
+		contributors = contributors[:maxChartBars]
+	}
+
+	maxChanges := 0
+	for _, c := range contributors {
+		if c.Additions+c.Deletions > maxChanges {
+			maxChanges = c.Additions + c.Deletions
+		}
+	}
+
+	chart := htmlBarChart{Height: len(contributors) * (chartBarHeight + 6)}
+	span := float64(chartWidth - chartLabelSpan - 80)
+	for i, c := range contributors {
+		bar := htmlBar{
+			Label:     c.Name,
+			Y:         i * (chartBarHeight + 6),
+			Additions: c.Additions,
+			Deletions: c.Deletions,
+		}
+		if c.Login != "" {
+			bar.Label = "@" + c.Login
+		}
+		if maxChanges > 0 {
+			bar.AddWidth = span * float64(c.Additions) / float64(maxChanges)
+			bar.DelWidth = span * float64(c.Deletions) / float64(maxChanges)
+		}
+		bar.DelX = float64(chartLabelSpan) + bar.AddWidth
+		chart.Bars = append(chart.Bars, bar)
+	}
+	return chart
+}
+
+// activityTimeline buckets commits into days, weeks or months depending on the period length
+func (r *Reporter) activityTimeline(period models.Period, commits []models.Commit) htmlTimeline {
+	since, until := period.Since, period.Until
+	if until.Before(since) {
+		return htmlTimeline{}
+	}
+
+	timeline := htmlTimeline{Unit: "day"}
+	start := time.Date(since.Year(), since.Month(), since.Day(), 0, 0, 0, 0, since.Location())
+	next := func(t time.Time) time.Time { return t.AddDate(0, 0, 1) }
+	label := func(t time.Time) string { return t.Format("2006-01-02") }
+
+	switch days := until.Sub(since).Hours() / 24; {
+	case days > 730:
+		timeline.Unit = "month"
+		start = time.Date(since.Year(), since.Month(), 1, 0, 0, 0, 0, since.Location())
+		next = func(t time.Time) time.Time { return t.AddDate(0, 1, 0) }
+		label = func(t time.Time) string { return t.Format("2006-01") }
+	case days > 62:
+		timeline.Unit = "week"
+		start = start.AddDate(0, 0, -int((start.Weekday()+6)%7)) // Monday
+		next = func(t time.Time) time.Time { return t.AddDate(0, 0, 7) }
+	}
+
+	var buckets []time.Time
+	for t := start; !t.After(until); t = next(t) {
+		buckets = append(buckets, t)
+	}
+	if len(buckets) == 0 {
+		return timeline
+	}
+
+	counts := make([]int, len(buckets))
+	for _, commit := range commits {
+		t := r.commitTime(commit)
+		i := sort.Search(len(buckets), func(i int) bool { return buckets[i].After(t) }) - 1
+		if i >= 0 && !t.After(until) {
+			counts[i]++
+		}
+	}
+
+	maxCount := 0
+	for _, count := range counts {
+		if count > maxCount {
+			maxCount = count
+		}
+	}
+
+	width := float64(chartWidth) / float64(len(buckets))
+	for i, count := range counts {
+		height := 0.0
+		if maxCount > 0 {
+			height = float64(timelineHeight-20) * float64(count) / float64(maxCount)
+		}
+		timeline.Columns = append(timeline.Columns, htmlColumn{
+			Label:   label(buckets[i]),
+			X:       float64(i) * width,
+			Y:       float64(timelineHeight-16) - height,
+			Width:   width * 0.85,
+			Height:  height,
+			Commits: count,
+		})
+	}
+	timeline.First = timeline.Columns[0].Label
+	timeline.Last = timeline.Columns[len(timeline.Columns)-1].Label
+	return timeline
+}
+
+// plural formats a count with its noun, e.g. "1 commit" or "3 branches"
+func plural(n int, word string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, word)
+	}
+	if strings.HasSuffix(word, "ch") || strings.HasSuffix(word, "s") {
+		return fmt.Sprintf("%d %ses", n, word)
+	}
+	return fmt.Sprintf("%d %ss", n, word)
+}
+
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"plural":  plural,
+	"date":    func(t time.Time) string { return t.Format("2006-01-02") },
+	"labelX":  func() int { return chartLabelSpan },
+	"width":   func() int { return chartWidth },
+	"theight": func() int { return timelineHeight },
+}).Parse(htmlReportTemplate))
+
+const htmlReportTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>GitHub Activity Report: {{.Report.Target}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; color: #1f2328; margin: 2rem auto; max-width: 1000px; padding: 0 1rem; }
+h1 { margin-bottom: 0.25rem; }
+.meta { color: #59636e; margin-top: 0; }
+.cards { display: flex; gap: 1rem; flex-wrap: wrap; margin: 1.5rem 0; }
+.card { border: 1px solid #d1d9e0; border-radius: 6px; padding: 0.75rem 1rem; min-width: 140px; }
+.card strong { display: block; font-size: 1.5rem; }
+table { border-collapse: collapse; width: 100%; margin: 0.5rem 0 1.5rem; }
+th, td { border-bottom: 1px solid #d1d9e0; padding: 0.4rem 0.6rem; text-align: left; }
+th { background: #f6f8fa; cursor: pointer; user-select: none; }
+th.sorted-asc::after { content: " \25B2"; }
+th.sorted-desc::after { content: " \25BC"; }
+td.num, th.num { text-align: right; font-variant-numeric: tabular-nums; }
+.add { color: #1a7f37; fill: #2da44e; }
+.del { color: #cf222e; fill: #e5534b; }
+.col { fill: #0969da; }
+svg text { font-size: 12px; fill: #1f2328; }
+details { border: 1px solid #d1d9e0; border-radius: 6px; padding: 0.5rem 1rem; margin-bottom: 0.75rem; }
+summary { cursor: pointer; font-weight: 600; }
+.warning { background: #fff8c5; border: 1px solid #d4a72c; border-radius: 6px; padding: 0.75rem 1rem; }
+</style>
+</head>
+<body>
+<h1>GitHub Activity Report: {{.Report.Target}}</h1>
+<p class="meta">Period: {{date .Report.Period.Since}} to {{date .Report.Period.Until}} &middot; Generated {{.Generated}}</p>
+{{with .Incomplete}}<p class="warning"><strong>Warning:</strong> this is an {{.}}. Skipped: {{$.Skipped}}</p>{{end}}
+
+<div class="cards">
+<div class="card"><strong>{{len .Report.Repositories}}</strong>repositories</div>
+<div class="card"><strong>{{len .Contributors}}</strong>contributors</div>
+<div class="card"><strong>{{.Totals.Commits}}</strong>commits</div>
+<div class="card"><strong class="add">+{{.Totals.Additions}}</strong>additions</div>
+<div class="card"><strong class="del">-{{.Totals.Deletions}}</strong>deletions</div>
+</div>
+
+<h2>Activity</h2>
+{{template "timeline" .Timeline}}
+
+<h2>Top Contributors</h2>
+{{with .Chart}}{{if .Bars}}
+<svg role="img" aria-label="Additions and deletions per contributor" width="100%" viewBox="0 0 {{width}} {{.Height}}">
+{{range .Bars}}<g>
+<title>{{.Label}}: +{{.Additions}}/-{{.Deletions}}</title>
+<text x="{{labelX}}" y="{{.Y}}" dy="13" dx="-8" text-anchor="end">{{.Label}}</text>
+<rect class="add" x="{{labelX}}" y="{{.Y}}" width="{{.AddWidth}}" height="18"></rect>
+<rect class="del" x="{{.DelX}}" y="{{.Y}}" width="{{.DelWidth}}" height="18"></rect>
+</g>
+{{end}}</svg>
+{{else}}<p>No contributions in this period.</p>{{end}}{{end}}
+
+<h2>Contributors</h2>
+<table class="sortable">
+<thead><tr><th>Contributor</th><th>Login</th><th class="num">Commits</th><th class="num">Additions</th><th class="num">Deletions</th><th class="num">Repositories</th></tr></thead>
+<tbody>
+{{range .Contributors}}<tr><td>{{.Name}}</td><td>{{if .Login}}@{{.Login}}{{end}}</td><td class="num">{{.Commits}}</td><td class="num add">+{{.Additions}}</td><td class="num del">-{{.Deletions}}</td><td class="num">{{.Repositories}}</td></tr>
+{{end}}</tbody>
+</table>
+
+{{with .Teams}}
+<h2>Teams</h2>
+<table class="sortable">
+<thead><tr><th>Team</th><th class="num">Active Members</th><th class="num">Commits</th><th class="num">Additions</th><th class="num">Deletions</th><th class="num">Repositories</th></tr></thead>
+<tbody>
+{{range .}}<tr><td>{{.Name}}</td><td class="num" data-sort="{{.Active}}">{{.Active}} / {{.Members}}</td><td class="num">{{.Totals.Commits}}</td><td class="num add">+{{.Totals.Additions}}</td><td class="num del">-{{.Totals.Deletions}}</td><td class="num">{{len .Repositories}}</td></tr>
+{{end}}</tbody>
+</table>
+{{range .}}{{if .Repositories}}<details>
+<summary>{{.Name}} &mdash; {{plural .Totals.Commits "commit"}} (<span class="add">+{{.Totals.Additions}}</span>/<span class="del">-{{.Totals.Deletions}}</span>)</summary>
+<table class="sortable">
+<thead><tr><th>Repository</th><th class="num">Commits</th><th class="num">Additions</th><th class="num">Deletions</th></tr></thead>
+<tbody>
+{{range .Repositories}}<tr><td>{{.FullName}}</td><td class="num">{{.Commits}}</td><td class="num add">+{{.Additions}}</td><td class="num del">-{{.Deletions}}</td></tr>
+{{end}}</tbody>
+</table>
+</details>
+{{end}}{{end}}{{end}}
+<h2>Repositories</h2>
+{{range .Repositories}}<details>
+<summary>{{.FullName}} &mdash; {{plural .Totals.Commits "commit"}} (<span class="add">+{{.Totals.Additions}}</span>/<span class="del">-{{.Totals.Deletions}}</span>)</summary>
+<p class="meta">{{if .URL}}<a href="{{.URL}}">{{.URL}}</a> &middot; {{end}}{{plural .Branches "branch"}} analyzed</p>
+{{template "timeline" .Timeline}}
+{{if .Contributors}}<table class="sortable">
+<thead><tr><th>Contributor</th><th class="num">Commits</th><th class="num">Additions</th><th class="num">Deletions</th></tr></thead>
+<tbody>
+{{range .Contributors}}<tr><td>{{.Name}}{{if .Login}} (@{{.Login}}){{end}}</td><td class="num">{{.Commits}}</td><td class="num add">+{{.Additions}}</td><td class="num del">-{{.Deletions}}</td></tr>
+{{end}}</tbody>
+</table>{{end}}
+</details>
+{{end}}
+{{with .Report.Diagnostics}}
+<h2>Diagnostics</h2>
+<p class="warning">Data could not be fetched for the following {{plural (len .) "item"}}, so totals may be understated.</p>
+<table class="sortable">
+<thead><tr><th>Stage</th><th>Repository</th><th>Branch</th><th>SHA</th><th>Category</th><th>Error</th></tr></thead>
+<tbody>
+{{range .}}<tr><td>{{.Stage}}</td><td>{{if .Team}}team {{.Team}}{{else}}{{.Repository}}{{end}}</td><td>{{.Branch}}</td><td>{{.SHA}}</td><td>{{.Category}}</td><td>{{.Error}}</td></tr>
+{{end}}</tbody>
+</table>
+{{end}}
+
+<script>
+document.querySelectorAll("table.sortable").forEach(function (table) {
+  table.querySelectorAll("th").forEach(function (th, column) {
+    th.addEventListener("click", function () {
+      var desc = !th.classList.contains("sorted-desc");
+      table.querySelectorAll("th").forEach(function (h) { h.classList.remove("sorted-asc", "sorted-desc"); });
+      th.classList.add(desc ? "sorted-desc" : "sorted-asc");
+      var numeric = th.classList.contains("num");
+      var body = table.tBodies[0];
+      Array.from(body.rows).sort(function (a, b) {
+        // Cells showing more than one number carry the one to sort by in data-sort
+        var x = a.cells[column].dataset.sort || a.cells[column].textContent;
+        var y = b.cells[column].dataset.sort || b.cells[column].textContent;
+        var cmp = numeric ? parseInt(x.replace(/[^0-9]/g, ""), 10) - parseInt(y.replace(/[^0-9]/g, ""), 10) : x.localeCompare(y);
+        return desc ? -cmp : cmp;
+      }).forEach(function (row) { body.appendChild(row); });
+    });
+  });
+});
+</script>
+</body>
+</html>
+{{define "timeline"}}{{if .Columns}}<svg role="img" aria-label="Commits per {{.Unit}}" width="100%" viewBox="0 0 {{width}} {{theight}}">
+{{range .Columns}}<rect class="col" x="{{.X}}" y="{{.Y}}" width="{{.Width}}" height="{{.Height}}"><title>{{.Label}}: {{.Commits}}</title></rect>
+{{end}}<text x="0" y="{{theight}}">{{.First}}</text>
+<text x="{{width}}" y="{{theight}}" text-anchor="end">{{.Last}}</text>
+</svg>{{end}}{{end}}`
@@ -0,0 +1,78 @@
+package reporter
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"ghreporting/internal/client"
+	"ghreporting/internal/models"
+)
+
+// IdentitySource selects whether the author or the committer of a commit is used
+type IdentitySource string
+
+const (
+	// IdentityAuthor uses the commit author and author date
+	IdentityAuthor IdentitySource = "author"
+	// IdentityCommitter uses the committer and commit date
+	IdentityCommitter IdentitySource = "committer"
+)
+
+// ParseIdentitySource validates an identity source name
+func ParseIdentitySource(value string) (IdentitySource, error) {
+	switch source := IdentitySource(strings.ToLower(value)); source {
+	case IdentityAuthor, IdentityCommitter:
+		return source, nil
+	default:
+		return "", fmt.Errorf("unsupported identity source: %s (expected author or committer)", value)
+	}
+}
+
+// commitIdentity returns the person a commit is attributed to. With pull request
+// attribution the pull request author takes precedence. Commits without committer
+// information (e.g. from older saved reports) fall back to the author.
+func (r *Reporter) commitIdentity(commit models.Commit) models.Author {
+	if r.prAttribution && commit.PullRequest != nil && commit.PullRequest.Author.Login != "" {
+		return commit.PullRequest.Author
+	}
+	if r.attribution == IdentityCommitter && commit.Committer != (models.Author{}) {
+		return commit.Committer
+	}
+	return commit.Author
+}
+
+// commitTime returns the time used to decide whether a commit falls in the report period
+func (r *Reporter) commitTime(commit models.Commit) time.Time {
+	if r.periodBasis == IdentityAuthor || commit.CommitDate.IsZero() {
+		return commit.Date
+	}
+	return commit.CommitDate
+}
+
+// inPeriod reports whether a commit falls within the period on the configured basis
+func (r *Reporter) inPeriod(commit models.Commit, since, until time.Time) bool {
+	t := r.commitTime(commit)
+	return !t.Before(since) && !t.After(until)
+}
+
+// listCommits fetches the commits of a branch that fall within the period.
+// The GitHub API filters on commit date, so when the period is based on author
+// dates the upper bound is dropped: rebased, cherry-picked and web-UI merged
+// commits are committed after they were authored. Commits already in the
+// baseline are skipped before their details are fetched.
+func (r *Reporter) listCommits(ctx context.Context, owner, repo, branch string, since, until time.Time) ([]models.Commit, []client.CommitError, error) {
+	known := r.baseline.known(owner+"/"+repo, branch)
+	if r.periodBasis != IdentityAuthor && len(known) == 0 {
+		return r.client.ListCommits(ctx, owner, repo, branch, since, until, nil)
+	}
+
+	apiUntil := until
+	if r.periodBasis == IdentityAuthor {
+		apiUntil = time.Time{}
+	}
+	return r.client.ListCommits(ctx, owner, repo, branch, since, apiUntil, func(commit models.Commit) bool {
+		return !known[commit.SHA] && r.inPeriod(commit, since, until)
+	})
+}
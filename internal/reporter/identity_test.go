@@ -0,0 +1,94 @@
+package reporter
+
+import (
+	"testing"
+	"time"
+
+	"ghreporting/internal/models"
+)
+
+func TestCommitAttribution(t *testing.T) {
+	commit := models.Commit{
+		SHA:       "abc123",
+		Message:   "Cherry-picked fix\n\nCo-authored-by: Jane Doe <jane@example.com>",
+		Author:    models.Author{Name: "John Doe", Email: "john@example.com", Login: "johndoe"},
+		Committer: models.Author{Name: "Release Bot", Email: "bot@example.com", Login: "release-bot"},
+		Stats:     models.CommitStats{Additions: 10, Deletions: 2, Total: 12},
+	}
+	repos := []models.Repository{
+		{
+			FullName: "owner/repo1",
+			Branches: []models.Branch{{Name: "main", Commits: []models.Commit{commit}}},
+		},
+	}
+
+	t.Run("Author", func(t *testing.T) {
+		r := &Reporter{attribution: IdentityAuthor, coAuthors: true}
+		summary := r.generateSummary(repos)
+
+		if _, exists := summary["johndoe"]; !exists {
+			t.Error("Expected commit to be credited to author 'johndoe'")
+		}
+		if _, exists := summary["jane@example.com"]; !exists {
+			t.Error("Expected co-author to be credited when attributing by author")
+		}
+	})
+
+	t.Run("Committer", func(t *testing.T) {
+		r := &Reporter{attribution: IdentityCommitter, coAuthors: true}
+		summary := r.generateSummary(repos)
+
+		if len(summary) != 1 {
+			t.Fatalf("Expected 1 contributor, got %d", len(summary))
+		}
+		if summary["release-bot"].TotalAdditions != 10 {
+			t.Errorf("Expected committer to be credited with 10 additions, got %d", summary["release-bot"].TotalAdditions)
+		}
+	})
+
+	t.Run("PullRequestAuthor", func(t *testing.T) {
+		squashed := commit
+		squashed.PullRequest = &models.PullRequest{
+			Number: 42,
+			Author: models.Author{Name: "contributor", Login: "contributor"},
+		}
+
+		r := &Reporter{attribution: IdentityAuthor, prAttribution: true}
+		if identity := r.commitIdentity(squashed); identity.Login != "contributor" {
+			t.Errorf("Expected pull request author, got %+v", identity)
+		}
+
+		r.prAttribution = false
+		if identity := r.commitIdentity(squashed); identity.Login != "johndoe" {
+			t.Errorf("Expected commit author when pull request attribution is disabled, got %+v", identity)
+		}
+	})
+
+	t.Run("CommitterFallback", func(t *testing.T) {
+		r := &Reporter{attribution: IdentityCommitter}
+		withoutCommitter := commit
+		withoutCommitter.Committer = models.Author{}
+
+		if identity := r.commitIdentity(withoutCommitter); identity.Login != "johndoe" {
+			t.Errorf("Expected fallback to author, got %+v", identity)
+		}
+	})
+}
+
+func TestInPeriod(t *testing.T) {
+	since := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+
+	// Authored inside the period, rebased and committed after it
+	commit := models.Commit{
+		Date:       time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+		CommitDate: time.Date(2024, 2, 10, 0, 0, 0, 0, time.UTC),
+	}
+
+	if !(&Reporter{periodBasis: IdentityAuthor}).inPeriod(commit, since, until) {
+		t.Error("Commit should be in period by author date")
+	}
+	if (&Reporter{periodBasis: IdentityCommitter}).inPeriod(commit, since, until) {
+		t.Error("Commit should not be in period by commit date")
+	}
+}
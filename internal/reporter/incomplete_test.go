@@ -0,0 +1,92 @@
+package reporter
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"ghreporting/internal/models"
+)
+
+func incompleteReport() *models.Report {
+	report := testReport()
+	report.Incomplete = newIncomplete("received interrupt", report.Repositories, []string{"owner/repo3", "owner/repo2"})
+	return report
+}
+
+func TestNewIncomplete(t *testing.T) {
+	incomplete := incompleteReport().Incomplete
+
+	if len(incomplete.FinishedRepositories) != 1 || incomplete.FinishedRepositories[0] != "owner/repo1" {
+		t.Errorf("Expected owner/repo1 to be finished, got %v", incomplete.FinishedRepositories)
+	}
+	if strings.Join(incomplete.SkippedRepositories, ",") != "owner/repo2,owner/repo3" {
+		t.Errorf("Expected sorted skipped repositories, got %v", incomplete.SkippedRepositories)
+	}
+
+	expected := "incomplete report: interrupted (received interrupt) after 1 of 3 repositories"
+	if notice := incompleteNotice(incompleteReport()); notice != expected {
+		t.Errorf("Expected %q, got %q", expected, notice)
+	}
+	if notice := incompleteNotice(testReport()); notice != "" {
+		t.Errorf("Expected no notice for a complete report, got %q", notice)
+	}
+}
+
+func TestIncompleteOutputs(t *testing.T) {
+	r := &Reporter{}
+	dir := t.TempDir()
+
+	for _, format := range []string{"text", "html", "markdown", "json"} {
+		outputFile := filepath.Join(dir, "report."+format)
+		if err := r.OutputReport(incompleteReport(), outputFile, format); err != nil {
+			t.Fatalf("Failed to write %s report: %v", format, err)
+		}
+		data, err := os.ReadFile(outputFile)
+		if err != nil {
+			t.Fatalf("Failed to read %s report: %v", format, err)
+		}
+		if !strings.Contains(string(data), "owner/repo2") {
+			t.Errorf("%s output should list skipped repositories", format)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := writeOpenMetrics(&buf, incompleteReport()); err != nil {
+		t.Fatalf("Failed to write OpenMetrics: %v", err)
+	}
+	if !strings.Contains(buf.String(), `ghreporting_report_complete{target="owner"} 0`) {
+		t.Errorf("OpenMetrics output should mark the report incomplete:\n%s", buf.String())
+	}
+}
+
+func TestMergeReportsIncomplete(t *testing.T) {
+	r := &Reporter{}
+
+	// A repository skipped by one report but finished by another is no longer missing
+	interrupted := incompleteReport()
+	interrupted.Repositories = nil
+	interrupted.Incomplete = newIncomplete("received interrupt", nil, []string{"owner/repo1", "owner/repo2"})
+
+	merged, err := r.MergeReports([]*models.Report{interrupted, testReport()})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if merged.Incomplete == nil || strings.Join(merged.Incomplete.SkippedRepositories, ",") != "owner/repo2" {
+		t.Errorf("Expected only owner/repo2 to remain skipped, got %+v", merged.Incomplete)
+	}
+
+	merged, err = r.MergeReports([]*models.Report{testReport(), testReport()})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if merged.Incomplete != nil {
+		t.Errorf("Expected complete reports to merge into a complete report, got %+v", merged.Incomplete)
+	}
+
+	if _, err := r.ExtendReport(incompleteReport(), testReport()); err == nil {
+		t.Error("Expected error when extending an incomplete report")
+	}
+}
@@ -0,0 +1,108 @@
+package reporter
+
+import (
+	"fmt"
+
+	"ghreporting/internal/models"
+)
+
+// baseline records what a previous report already contains so an incremental
+// run only fetches what changed since
+type baseline struct {
+	heads   map[string]string          // Branch head SHA by "owner/repo@branch"
+	commits map[string]map[string]bool // Known commit SHAs by "owner/repo@branch"
+}
+
+func branchKey(repo, branch string) string {
+	return repo + "@" + branch
+}
+
+// SetBaseline makes GenerateReport skip branches whose head has not moved since the
+// previous report and avoid fetching details for commits it already contains
+func (r *Reporter) SetBaseline(previous *models.Report) {
+	if previous == nil {
+		r.baseline = nil
+		return
+	}
+
+	r.baseline = &baseline{
+		heads:   make(map[string]string),
+		commits: make(map[string]map[string]bool),
+	}
+	for _, repo := range previous.Repositories {
+		for _, branch := range repo.Branches {
+			key := branchKey(repo.FullName, branch.Name)
+			if branch.SHA != "" {
+				r.baseline.heads[key] = branch.SHA
+			}
+			known := r.baseline.commits[key]
+			if known == nil {
+				known = make(map[string]bool)
+				r.baseline.commits[key] = known
+			}
+			for _, commit := range branch.Commits {
+				known[commit.SHA] = true
+			}
+		}
+	}
+}
+
+// unchanged reports whether the branch head is the one recorded in the baseline
+func (b *baseline) unchanged(repo string, branch models.Branch) bool {
+	return b != nil && branch.SHA != "" && b.heads[branchKey(repo, branch.Name)] == branch.SHA
+}
+
+// known returns the commit SHAs the baseline already has for the branch
+func (b *baseline) known(repo, branch string) map[string]bool {
+	if b == nil {
+		return nil
+	}
+	return b.commits[branchKey(repo, branch)]
+}
+
+// ExtendReport merges an incremental update into the previous report it was generated
+// from. The update must start no later than the previous report ends; the result covers
+// both periods and its summary is recomputed from the combined commits. Incomplete
+// reports cannot be extended because their skipped repositories lack earlier commits.
+func (r *Reporter) ExtendReport(previous, update *models.Report) (*models.Report, error) {
+	if previous.Incomplete != nil {
+		return nil, fmt.Errorf("previous report is incomplete (%d repositories skipped), generate it again in full",
+			len(previous.Incomplete.SkippedRepositories))
+	}
+	if update.Period.Since.After(previous.Period.Until) {
+		return nil, fmt.Errorf("update starting %s leaves a gap after the previous report ending %s",
+			update.Period.Since.Format("2006-01-02"), previous.Period.Until.Format("2006-01-02"))
+	}
+
+	extended := &models.Report{
+		Target: previous.Target,
+		Period: previous.Period,
+	}
+	if update.Period.Until.After(extended.Period.Until) {
+		extended.Period.Until = update.Period.Until
+	}
+
+	repoIndex := make(map[string]int)
+	mergeRepositories(extended, repoIndex, previous.Repositories)
+	mergeRepositories(extended, repoIndex, update.Repositories)
+
+	extended.Summary = r.generateSummary(extended.Repositories)
+	extended.Teams = r.generateTeams(extended.Repositories, r.teamMembers(update, previous))
+	if update.Incomplete != nil {
+		// Repositories skipped by the update lack its commits even when the previous
+		// report has them, so they stay missing
+		skipped := make(map[string]bool)
+		for _, name := range update.Incomplete.SkippedRepositories {
+			skipped[name] = true
+		}
+		var finished []models.Repository
+		for _, repo := range extended.Repositories {
+			if !skipped[repo.FullName] {
+				finished = append(finished, repo)
+			}
+		}
+		extended.Incomplete = newIncomplete(update.Incomplete.Reason, finished, update.Incomplete.SkippedRepositories)
+	}
+	extended.Diagnostics = combineDiagnostics([]*models.Report{previous, update})
+	return extended, nil
+}
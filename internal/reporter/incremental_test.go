@@ -0,0 +1,89 @@
+package reporter
+
+import (
+	"testing"
+	"time"
+
+	"ghreporting/internal/models"
+)
+
+func TestSetBaseline(t *testing.T) {
+	r := &Reporter{}
+	previous := testReport()
+	previous.Repositories[0].Branches[0].SHA = "abc123"
+	r.SetBaseline(previous)
+
+	if !r.baseline.unchanged("owner/repo1", models.Branch{Name: "main", SHA: "abc123"}) {
+		t.Error("Expected branch with the same head to be unchanged")
+	}
+	if r.baseline.unchanged("owner/repo1", models.Branch{Name: "main", SHA: "fff999"}) {
+		t.Error("Expected branch with a new head to be changed")
+	}
+	if r.baseline.unchanged("owner/repo2", models.Branch{Name: "main", SHA: "abc123"}) {
+		t.Error("Expected unknown repository to be changed")
+	}
+
+	known := r.baseline.known("owner/repo1", "main")
+	if !known["abc123"] || !known["def456"] || len(known) != 2 {
+		t.Errorf("Expected both commits to be known, got %v", known)
+	}
+
+	r.SetBaseline(nil)
+	if r.baseline.unchanged("owner/repo1", models.Branch{Name: "main", SHA: "abc123"}) || r.baseline.known("owner/repo1", "main") != nil {
+		t.Error("Expected no baseline after clearing it")
+	}
+}
+
+func TestExtendReport(t *testing.T) {
+	r := &Reporter{}
+	previous := testReport()
+	previous.Repositories[0].Branches[0].SHA = "def456"
+
+	update := &models.Report{
+		Target: "owner",
+		Period: models.Period{
+			Since: time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+			Until: time.Date(2024, 2, 2, 0, 0, 0, 0, time.UTC),
+		},
+		Repositories: []models.Repository{{
+			Name:     "repo1",
+			FullName: "owner/repo1",
+			Branches: []models.Branch{{
+				Name: "main",
+				SHA:  "fff999",
+				Commits: []models.Commit{
+					{
+						SHA:    "fff999",
+						Author: models.Author{Name: "John Doe", Email: "john@example.com", Login: "johndoe"},
+						Date:   time.Date(2024, 2, 1, 10, 0, 0, 0, time.UTC),
+						Stats:  models.CommitStats{Additions: 7, Deletions: 2, Total: 9},
+					},
+				},
+			}},
+		}},
+	}
+
+	extended, err := r.ExtendReport(previous, update)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !extended.Period.Since.Equal(previous.Period.Since) || !extended.Period.Until.Equal(update.Period.Until) {
+		t.Errorf("Expected period 2024-01-01 to 2024-02-02, got %s to %s", extended.Period.Since, extended.Period.Until)
+	}
+	branch := extended.Repositories[0].Branches[0]
+	if len(branch.Commits) != 3 {
+		t.Errorf("Expected 3 commits, got %d", len(branch.Commits))
+	}
+	if branch.SHA != "fff999" {
+		t.Errorf("Expected branch head fff999, got %s", branch.SHA)
+	}
+	if extended.Summary["johndoe"].TotalCommits != 2 {
+		t.Errorf("Expected 2 commits for johndoe, got %d", extended.Summary["johndoe"].TotalCommits)
+	}
+
+	update.Period.Since = time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	if _, err := r.ExtendReport(previous, update); err == nil {
+		t.Error("Expected error for a gap between reports")
+	}
+}
@@ -0,0 +1,179 @@
+package reporter
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"sort"
+	"strings"
+
+	"ghreporting/internal/models"
+)
+
+// Number of repositories listed in the Markdown "Top Repositories" table
+const markdownTopRepositories = 10
+
+func (r *Reporter) outputMarkdown(report *models.Report, outputFile string) error {
+	output, err := openOutput(outputFile)
+	if err != nil {
+		return err
+	}
+	defer output.Close()
+
+	return r.writeMarkdown(output, report)
+}
+
+func (r *Reporter) writeMarkdown(output io.Writer, report *models.Report) error {
+	contributors := r.sortedContributors(report)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# GitHub Activity Report: %s\n\n", markdownEscape(report.Target))
+	fmt.Fprintf(&b, "**Period:** %s to %s · **Repositories analyzed:** %d · **Contributors:** %d\n\n",
+		report.Period.Since.Format("2006-01-02"), report.Period.Until.Format("2006-01-02"),
+		len(report.Repositories), len(contributors))
+	if notice := incompleteNotice(report); notice != "" {
+		fmt.Fprintf(&b, "> [!WARNING]\n> This is an %s. Skipped: %s\n\n",
+			markdownEscape(notice), markdownEscape(strings.Join(report.Incomplete.SkippedRepositories, ", ")))
+	}
+
+	// Contributor summary
+	fmt.Fprintf(&b, "## Contributor Summary\n\n")
+	if len(contributors) == 0 {
+		fmt.Fprintf(&b, "_No contributions in this period._\n\n")
+	} else {
+		fmt.Fprintf(&b, "| # | Contributor | Commits | Additions | Deletions | Repositories |\n")
+		fmt.Fprintf(&b, "|--:|-------------|--------:|----------:|----------:|-------------:|\n")
+		for i, contributor := range contributors {
+			stats := report.Summary[contributor]
+			fmt.Fprintf(&b, "| %d | %s | %d | +%d | -%d | %d |\n",
+				i+1, markdownContributor(stats), stats.TotalCommits, stats.TotalAdditions, stats.TotalDeletions, len(stats.Repositories))
+		}
+		fmt.Fprintf(&b, "\n")
+	}
+
+	// Top repositories
+	type repoRow struct {
+		name         string
+		totals       models.RepositoryStats
+		contributors int
+	}
+	var rows []repoRow
+	for _, repo := range report.Repositories {
+		row := repoRow{name: repo.FullName, totals: repositoryTotals(repo)}
+		for _, stats := range report.Summary {
+			if _, exists := stats.Repositories[repo.FullName]; exists {
+				row.contributors++
+			}
+		}
+		if row.totals.Commits > 0 {
+			rows = append(rows, row)
+		}
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, b := rows[i].totals, rows[j].totals
+		return (a.Additions + a.Deletions) > (b.Additions + b.Deletions)
+	})
+
+	if len(rows) > 0 {
+		fmt.Fprintf(&b, "## Top Repositories\n\n")
+		fmt.Fprintf(&b, "| Repository | Commits | Additions | Deletions | Contributors |\n")
+		fmt.Fprintf(&b, "|------------|--------:|----------:|----------:|-------------:|\n")
+		for i, row := range rows {
+			if i >= markdownTopRepositories {
+				break
+			}
+			fmt.Fprintf(&b, "| %s | %d | +%d | -%d | %d |\n",
+				markdownEscape(row.name), row.totals.Commits, row.totals.Additions, row.totals.Deletions, row.contributors)
+		}
+		fmt.Fprintf(&b, "\n")
+	}
+
+	// Per-contributor details, collapsed so long reports stay readable
+	if len(contributors) > 0 {
+		fmt.Fprintf(&b, "## Contributor Details\n\n")
+	}
+	for _, contributor := range contributors {
+		stats := report.Summary[contributor]
+		fmt.Fprintf(&b, "<details>\n<summary><strong>%s</strong>", html.EscapeString(stats.Name))
+		if stats.Login != "" {
+			fmt.Fprintf(&b, " (@%s)", html.EscapeString(stats.Login))
+		}
+		fmt.Fprintf(&b, " — %s, +%d/-%d</summary>\n\n", plural(stats.TotalCommits, "commit"), stats.TotalAdditions, stats.TotalDeletions)
+
+		fmt.Fprintf(&b, "| Repository | Commits | Additions | Deletions |\n")
+		fmt.Fprintf(&b, "|------------|--------:|----------:|----------:|\n")
+		for _, repoName := range sortedRepositories(stats) {
+			repoStats := stats.Repositories[repoName]
+			fmt.Fprintf(&b, "| %s | %d | +%d | -%d |\n", markdownEscape(repoName), repoStats.Commits, repoStats.Additions, repoStats.Deletions)
+		}
+		fmt.Fprintf(&b, "\n</details>\n\n")
+	}
+
+	// Team summary and per-team repository breakdown
+	if len(report.Teams) > 0 {
+		teams := r.sortedTeams(report)
+		fmt.Fprintf(&b, "## Team Summary\n\n")
+		fmt.Fprintf(&b, "| Team | Active Members | Commits | Additions | Deletions | Repositories |\n")
+		fmt.Fprintf(&b, "|------|---------------:|--------:|----------:|----------:|-------------:|\n")
+		for _, name := range teams {
+			team := report.Teams[name]
+			fmt.Fprintf(&b, "| %s | %d / %d | %d | +%d | -%d | %d |\n",
+				markdownEscape(team.Name), len(team.Contributors), team.MemberCount, team.TotalCommits, team.TotalAdditions, team.TotalDeletions, len(team.Repositories))
+		}
+		fmt.Fprintf(&b, "\n")
+
+		for _, name := range teams {
+			team := report.Teams[name]
+			if len(team.Repositories) == 0 {
+				continue
+			}
+			fmt.Fprintf(&b, "<details>\n<summary><strong>%s</strong> — %s, +%d/-%d</summary>\n\n",
+				html.EscapeString(team.Name), plural(team.TotalCommits, "commit"), team.TotalAdditions, team.TotalDeletions)
+			fmt.Fprintf(&b, "| Repository | Commits | Additions | Deletions |\n")
+			fmt.Fprintf(&b, "|------------|--------:|----------:|----------:|\n")
+			for _, repoName := range teamRepositories(team) {
+				repoStats := team.Repositories[repoName]
+				fmt.Fprintf(&b, "| %s | %d | +%d | -%d |\n", markdownEscape(repoName), repoStats.Commits, repoStats.Additions, repoStats.Deletions)
+			}
+			fmt.Fprintf(&b, "\n</details>\n\n")
+		}
+	}
+
+	if len(report.Diagnostics) > 0 {
+		fmt.Fprintf(&b, "## Diagnostics\n\n")
+		fmt.Fprintf(&b, "Data could not be fetched for %s, so totals may be understated.\n\n", plural(len(report.Diagnostics), "item"))
+		fmt.Fprintf(&b, "| Stage | Location | Category | Error |\n")
+		fmt.Fprintf(&b, "|-------|----------|----------|-------|\n")
+		for _, entry := range report.Diagnostics {
+			fmt.Fprintf(&b, "| %s | %s | %s | %s |\n", entry.Stage, markdownEscape(diagnosticLocation(entry)), entry.Category, markdownEscape(entry.Error))
+		}
+		fmt.Fprintf(&b, "\n")
+	}
+
+	_, err := io.WriteString(output, b.String())
+	return err
+}
+
+// markdownContributor renders a contributor name with a link to their GitHub profile
+func markdownContributor(stats models.ContributorStats) string {
+	if stats.Login == "" {
+		return markdownEscape(stats.Name)
+	}
+	return fmt.Sprintf("%s ([@%s](https://github.com/%s))", markdownEscape(stats.Name), stats.Login, stats.Login)
+}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	`|`, `\|`,
+	`*`, `\*`,
+	`_`, `\_`,
+	"`", "\\`",
+	`<`, `&lt;`,
+	`>`, `&gt;`,
+	"\n", " ",
+)
+
+// markdownEscape makes text safe for use inside a Markdown table cell
+func markdownEscape(text string) string {
+	return markdownEscaper.Replace(text)
+}
@@ -0,0 +1,156 @@
+package reporter
+
+import (
+	"fmt"
+	"strings"
+
+	"ghreporting/internal/models"
+)
+
+// MergeReports combines reports generated for the same period, e.g. one per organization
+// from parallel jobs. Repositories are unioned by full name and branches by name; a commit
+// seen on the same branch in several reports is kept once. The summary and teams are
+// recomputed from the merged commits, counting a commit on several branches of a
+// repository once.
+func (r *Reporter) MergeReports(reports []*models.Report) (*models.Report, error) {
+	if len(reports) == 0 {
+		return nil, fmt.Errorf("no reports to merge")
+	}
+
+	merged := &models.Report{Period: reports[0].Period}
+	var targets []string
+	seenTargets := make(map[string]bool)
+	repoIndex := make(map[string]int)
+
+	for i, report := range reports {
+		if err := checkPeriodsCompatible(reports[0].Period, report.Period); err != nil {
+			return nil, fmt.Errorf("report %d (%s): %w", i+1, report.Target, err)
+		}
+		if report.Period.Since.Before(merged.Period.Since) {
+			merged.Period.Since = report.Period.Since
+		}
+		if report.Period.Until.After(merged.Period.Until) {
+			merged.Period.Until = report.Period.Until
+		}
+
+		for _, target := range strings.Split(report.Target, ",") {
+			if target != "" && !seenTargets[target] {
+				seenTargets[target] = true
+				targets = append(targets, target)
+			}
+		}
+
+		mergeRepositories(merged, repoIndex, report.Repositories)
+	}
+
+	merged.Target = strings.Join(targets, ",")
+	unique := uniqueRepositories(merged.Repositories)
+	merged.Summary = r.generateSummary(unique)
+	merged.Teams = r.generateTeams(unique, r.teamMembers(reports...))
+	merged.Incomplete = combineIncomplete(reports, merged.Repositories)
+	merged.Diagnostics = combineDiagnostics(reports)
+	return merged, nil
+}
+
+// uniqueRepositories returns copies of repos holding their unique commits on a single
+// branch, so summaries count a commit reachable from several branches once
+func uniqueRepositories(repos []models.Repository) []models.Repository {
+	unique := make([]models.Repository, len(repos))
+	for i, repo := range repos {
+		unique[i] = repo
+		unique[i].Branches = []models.Branch{{Commits: uniqueCommits(repo)}}
+	}
+	return unique
+}
+
+// combineIncomplete merges the interruption records of reports, returning nil when none
+// is incomplete. The reports share a period, so a repository skipped by one report is no
+// longer missing if another report finished it.
+func combineIncomplete(reports []*models.Report, repos []models.Repository) *models.Incomplete {
+	var reasons []string
+	skipped := make(map[string]bool)
+	for _, report := range reports {
+		if report.Incomplete == nil {
+			continue
+		}
+		reasons = append(reasons, report.Incomplete.Reason)
+		for _, repo := range report.Incomplete.SkippedRepositories {
+			skipped[repo] = true
+		}
+	}
+	if len(reasons) == 0 {
+		return nil
+	}
+
+	var finished []models.Repository
+	for _, repo := range repos {
+		delete(skipped, repo.FullName)
+		finished = append(finished, repo)
+	}
+	if len(skipped) == 0 {
+		return nil
+	}
+
+	var missing []string
+	for repo := range skipped {
+		missing = append(missing, repo)
+	}
+	return newIncomplete(strings.Join(reasons, "; "), finished, missing)
+}
+
+// mergeRepositories adds repositories to the report, merging those it already has by full name
+func mergeRepositories(report *models.Report, index map[string]int, repos []models.Repository) {
+	for _, repo := range repos {
+		i, exists := index[repo.FullName]
+		if !exists {
+			repoCopy := repo
+			repoCopy.Branches = nil
+			report.Repositories = append(report.Repositories, repoCopy)
+			i = len(report.Repositories) - 1
+			index[repo.FullName] = i
+		}
+		mergeBranches(&report.Repositories[i], repo.Branches)
+	}
+}
+
+// mergeBranches adds branches to repo, skipping commits already recorded on the same
+// branch. Branch heads are taken from the last report that recorded one.
+func mergeBranches(repo *models.Repository, branches []models.Branch) {
+	for _, branch := range branches {
+		var target *models.Branch
+		for i := range repo.Branches {
+			if repo.Branches[i].Name == branch.Name {
+				target = &repo.Branches[i]
+				break
+			}
+		}
+		if target == nil {
+			repo.Branches = append(repo.Branches, models.Branch{Name: branch.Name, SHA: branch.SHA})
+			target = &repo.Branches[len(repo.Branches)-1]
+		} else if branch.SHA != "" {
+			target.SHA = branch.SHA
+		}
+
+		seen := make(map[string]bool, len(target.Commits))
+		for _, commit := range target.Commits {
+			seen[commit.SHA] = true
+		}
+		for _, commit := range branch.Commits {
+			if !seen[commit.SHA] {
+				seen[commit.SHA] = true
+				target.Commits = append(target.Commits, commit)
+			}
+		}
+	}
+}
+
+// checkPeriodsCompatible requires periods to cover the same calendar days. Reports
+// generated with default dates a few minutes apart still merge cleanly.
+func checkPeriodsCompatible(a, b models.Period) error {
+	const day = "2006-01-02"
+	if a.Since.UTC().Format(day) != b.Since.UTC().Format(day) || a.Until.UTC().Format(day) != b.Until.UTC().Format(day) {
+		return fmt.Errorf("period %s to %s does not match %s to %s",
+			b.Since.Format(day), b.Until.Format(day), a.Since.Format(day), a.Until.Format(day))
+	}
+	return nil
+}
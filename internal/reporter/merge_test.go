@@ -0,0 +1,103 @@
+package reporter
+
+import (
+	"testing"
+	"time"
+
+	"ghreporting/internal/models"
+)
+
+func TestMergeReports(t *testing.T) {
+	r := &Reporter{}
+
+	first := testReport()
+	second := testReport()
+	second.Target = "other"
+	second.Repositories = append(second.Repositories, models.Repository{
+		Name:     "tools",
+		FullName: "other/tools",
+		Branches: []models.Branch{{
+			Name: "main",
+			Commits: []models.Commit{{
+				SHA:    "fff999",
+				Author: models.Author{Name: "John Doe", Email: "john@example.com", Login: "johndoe"},
+				Date:   time.Date(2024, 1, 5, 10, 0, 0, 0, time.UTC),
+				Stats:  models.CommitStats{Additions: 7, Deletions: 2, Total: 9},
+			}},
+		}},
+	})
+	second.Summary = r.generateSummary(second.Repositories)
+
+	merged, err := r.MergeReports([]*models.Report{first, second})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if merged.Target != "owner,other" {
+		t.Errorf("Expected target owner,other, got %s", merged.Target)
+	}
+	if len(merged.Repositories) != 2 {
+		t.Fatalf("Expected 2 repositories, got %d", len(merged.Repositories))
+	}
+	if commits := len(merged.Repositories[0].Branches[0].Commits); commits != 2 {
+		t.Errorf("Expected duplicate commits to be merged into 2, got %d", commits)
+	}
+
+	john := merged.Summary["johndoe"]
+	if john.TotalCommits != 2 || john.TotalAdditions != 17 {
+		t.Errorf("Expected johndoe with 2 commits and 17 additions, got %d and %d", john.TotalCommits, john.TotalAdditions)
+	}
+	if len(john.Repositories) != 2 {
+		t.Errorf("Expected johndoe in 2 repositories, got %d", len(john.Repositories))
+	}
+	if len(first.Repositories[0].Branches[0].Commits) != 2 {
+		t.Error("Merging should not modify the input reports")
+	}
+}
+
+func TestMergeReportsPeriods(t *testing.T) {
+	r := &Reporter{}
+
+	// Same days with different times of day are compatible
+	shifted := testReport()
+	shifted.Period.Since = shifted.Period.Since.Add(5 * time.Minute)
+	if _, err := r.MergeReports([]*models.Report{testReport(), shifted}); err != nil {
+		t.Errorf("Expected periods on the same days to merge, got %v", err)
+	}
+
+	mismatched := testReport()
+	mismatched.Period.Until = time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)
+	if _, err := r.MergeReports([]*models.Report{testReport(), mismatched}); err == nil {
+		t.Error("Expected error for mismatched periods")
+	}
+
+	if _, err := r.MergeReports(nil); err == nil {
+		t.Error("Expected error for no reports")
+	}
+}
+
+func TestMergeReportsCommitOnSeveralBranches(t *testing.T) {
+	r := &Reporter{}
+
+	first := testReport()
+	second := testReport()
+	// The second report saw the same commits on another branch of the repository
+	second.Repositories[0].Branches[0].Name = "develop"
+
+	merged, err := r.MergeReports([]*models.Report{first, second})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if branches := len(merged.Repositories[0].Branches); branches != 2 {
+		t.Fatalf("Expected 2 branches, got %d", branches)
+	}
+	for login, stats := range first.Summary {
+		if merged.Summary[login].TotalCommits != stats.TotalCommits {
+			t.Errorf("Expected %s with %d commits, got %d", login, stats.TotalCommits, merged.Summary[login].TotalCommits)
+		}
+		if merged.Summary[login].TotalAdditions != stats.TotalAdditions {
+			t.Errorf("Expected %s with %d additions, got %d", login, stats.TotalAdditions, merged.Summary[login].TotalAdditions)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package reporter
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"ghreporting/internal/models"
+)
+
+// Prefix of every exported metric name
+const metricsNamespace = "ghreporting"
+
+type metricSample struct {
+	labels [][2]string
+	value  int64
+}
+
+type metricFamily struct {
+	name    string
+	help    string
+	samples []metricSample
+}
+
+func (r *Reporter) outputOpenMetrics(report *models.Report, outputFile string) error {
+	output, err := openOutput(outputFile)
+	if err != nil {
+		return err
+	}
+	defer output.Close()
+
+	return writeOpenMetrics(output, report)
+}
+
+// writeOpenMetrics renders the report summary and repository totals as OpenMetrics
+// text. The output is also valid Prometheus text format, so it can be dropped into
+// the node_exporter textfile collector directory.
+func writeOpenMetrics(output io.Writer, report *models.Report) error {
+	target := [2]string{"target", report.Target}
+
+	families := []*metricFamily{
+		{name: "report_period_start_seconds", help: "Start of the report period as a Unix timestamp."},
+		{name: "report_period_end_seconds", help: "End of the report period as a Unix timestamp."},
+		{name: "report_complete", help: "Whether every repository was processed (0 when the run was interrupted)."},
+		{name: "report_repositories", help: "Repositories analyzed in the report."},
+		{name: "report_diagnostics", help: "Repositories, branches or commits skipped because of an error, by category."},
+		{name: "report_contributors", help: "Contributors with at least one commit in the report period."},
+		{name: "repository_commits", help: "Unique commits in a repository during the report period."},
+		{name: "repository_additions", help: "Lines added in a repository during the report period."},
+		{name: "repository_deletions", help: "Lines deleted in a repository during the report period."},
+		{name: "repository_contributors", help: "Contributors to a repository during the report period."},
+		{name: "contributor_commits", help: "Commits credited to a contributor in a repository during the report period."},
+		{name: "contributor_additions", help: "Lines added by a contributor in a repository during the report period."},
+		{name: "contributor_deletions", help: "Lines deleted by a contributor in a repository during the report period."},
+		{name: "team_members", help: "Configured members of a team."},
+		{name: "team_contributors", help: "Members of a team with at least one commit in the report period."},
+		{name: "team_commits", help: "Commits credited to members of a team in a repository during the report period, counting shared commits once."},
+		{name: "team_additions", help: "Lines added by members of a team in a repository during the report period."},
+		{name: "team_deletions", help: "Lines deleted by members of a team in a repository during the report period."},
+	}
+	byName := make(map[string]*metricFamily)
+	for _, family := range families {
+		byName[family.name] = family
+	}
+	add := func(name string, value int, labels ...[2]string) {
+		family := byName[name]
+		family.samples = append(family.samples, metricSample{labels: labels, value: int64(value)})
+	}
+
+	byName["report_period_start_seconds"].samples = []metricSample{{labels: [][2]string{target}, value: report.Period.Since.Unix()}}
+	byName["report_period_end_seconds"].samples = []metricSample{{labels: [][2]string{target}, value: report.Period.Until.Unix()}}
+	complete := 1
+	if report.Incomplete != nil {
+		complete = 0
+	}
+	add("report_complete", complete, target)
+	add("report_repositories", len(report.Repositories), target)
+	counts := diagnosticCounts(report)
+	categories := make([]string, 0, len(counts))
+	for category := range counts {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+	for _, category := range categories {
+		add("report_diagnostics", counts[category], target, [2]string{"category", category})
+	}
+	add("report_contributors", len(report.Summary), target)
+
+	repoContributors := make(map[string]int)
+	for _, stats := range report.Summary {
+		for repoName := range stats.Repositories {
+			repoContributors[repoName]++
+		}
+	}
+
+	repos := append([]models.Repository(nil), report.Repositories...)
+	sort.Slice(repos, func(i, j int) bool { return repos[i].FullName < repos[j].FullName })
+	for _, repo := range repos {
+		totals := repositoryTotals(repo)
+		labels := [][2]string{target, {"repo", repo.FullName}}
+		add("repository_commits", totals.Commits, labels...)
+		add("repository_additions", totals.Additions, labels...)
+		add("repository_deletions", totals.Deletions, labels...)
+		add("repository_contributors", repoContributors[repo.FullName], labels...)
+	}
+
+	var contributors []string
+	for contributor := range report.Summary {
+		contributors = append(contributors, contributor)
+	}
+	sort.Strings(contributors)
+	for _, contributor := range contributors {
+		stats := report.Summary[contributor]
+		var repoNames []string
+		for repoName := range stats.Repositories {
+			repoNames = append(repoNames, repoName)
+		}
+		sort.Strings(repoNames)
+
+		for _, repoName := range repoNames {
+			repoStats := stats.Repositories[repoName]
+			labels := [][2]string{target, {"contributor", contributor}, {"repo", repoName}}
+			add("contributor_commits", repoStats.Commits, labels...)
+			add("contributor_additions", repoStats.Additions, labels...)
+			add("contributor_deletions", repoStats.Deletions, labels...)
+		}
+	}
+
+	var teams []string
+	for name := range report.Teams {
+		teams = append(teams, name)
+	}
+	sort.Strings(teams)
+	for _, name := range teams {
+		team := report.Teams[name]
+		label := [2]string{"team", name}
+		add("team_members", team.MemberCount, target, label)
+		add("team_contributors", len(team.Contributors), target, label)
+
+		var repoNames []string
+		for repoName := range team.Repositories {
+			repoNames = append(repoNames, repoName)
+		}
+		sort.Strings(repoNames)
+		for _, repoName := range repoNames {
+			repoStats := team.Repositories[repoName]
+			labels := [][2]string{target, label, {"repo", repoName}}
+			add("team_commits", repoStats.Commits, labels...)
+			add("team_additions", repoStats.Additions, labels...)
+			add("team_deletions", repoStats.Deletions, labels...)
+		}
+	}
+
+	var b strings.Builder
+	for _, family := range families {
+		name := metricsNamespace + "_" + family.name
+		fmt.Fprintf(&b, "# HELP %s %s\n", name, family.help)
+		fmt.Fprintf(&b, "# TYPE %s gauge\n", name)
+		if strings.HasSuffix(name, "_seconds") {
+			fmt.Fprintf(&b, "# UNIT %s seconds\n", name)
+		}
+		for _, sample := range family.samples {
+			b.WriteString(name)
+			b.WriteByte('{')
+			for i, label := range sample.labels {
+				if i > 0 {
+					b.WriteByte(',')
+				}
+				fmt.Fprintf(&b, `%s="%s"`, label[0], escapeLabelValue(label[1]))
+			}
+			fmt.Fprintf(&b, "} %d\n", sample.value)
+		}
+	}
+	b.WriteString("# EOF\n")
+
+	_, err := io.WriteString(output, b.String())
+	return err
+}
+
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabelValue(value string) string {
+	return labelValueEscaper.Replace(value)
+}
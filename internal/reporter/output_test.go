@@ -0,0 +1,169 @@
+package reporter
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"ghreporting/internal/models"
+)
+
+func testReport() *models.Report {
+	r := &Reporter{}
+	repos := []models.Repository{
+		{
+			Name:          "repo1",
+			FullName:      "owner/repo1",
+			URL:           "https://github.com/owner/repo1",
+			DefaultBranch: "main",
+			Branches: []models.Branch{
+				{
+					Name: "main",
+					Commits: []models.Commit{
+						{
+							SHA:     "abc123",
+							Message: "Add feature\n\nLonger description",
+							Author:  models.Author{Name: "John Doe", Email: "john@example.com", Login: "johndoe"},
+							Date:    time.Date(2024, 1, 3, 10, 0, 0, 0, time.UTC),
+							Stats:   models.CommitStats{Additions: 10, Deletions: 5, Total: 15},
+						},
+						{
+							SHA:     "def456",
+							Message: "Fix <script> escaping",
+							Author:  models.Author{Name: "Jane Smith", Email: "jane@example.com", Login: "janesmith"},
+							Date:    time.Date(2024, 1, 20, 10, 0, 0, 0, time.UTC),
+							Stats:   models.CommitStats{Additions: 3, Deletions: 1, Total: 4},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	return &models.Report{
+		Target: "owner",
+		Period: models.Period{
+			Since: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			Until: time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+		},
+		Repositories: repos,
+		Summary:      r.generateSummary(repos),
+	}
+}
+
+func TestOutputHTML(t *testing.T) {
+	r := &Reporter{}
+	outputFile := filepath.Join(t.TempDir(), "report.html")
+
+	if err := r.OutputReport(testReport(), outputFile, "html"); err != nil {
+		t.Fatalf("Failed to write HTML report: %v", err)
+	}
+
+	data, err := os.ReadFile(outputFile)
+	if err != nil {
+		t.Fatalf("Failed to read HTML report: %v", err)
+	}
+	result := string(data)
+
+	for _, expected := range []string{"<!DOCTYPE html>", "GitHub Activity Report: owner", "@johndoe", "owner/repo1", "<svg", "2024-01-01 to 2024-01-31"} {
+		if !strings.Contains(result, expected) {
+			t.Errorf("HTML output should contain %q", expected)
+		}
+	}
+
+	for _, external := range []string{"<link", "<script src", "<img"} {
+		if strings.Contains(result, external) {
+			t.Errorf("HTML output should not reference external assets (%s)", external)
+		}
+	}
+}
+
+func TestActivityTimeline(t *testing.T) {
+	r := &Reporter{}
+	report := testReport()
+
+	timeline := r.activityTimeline(report.Period, uniqueCommits(report.Repositories[0]))
+	if timeline.Unit != "day" {
+		t.Errorf("Expected daily buckets for a one month period, got %s", timeline.Unit)
+	}
+	if len(timeline.Columns) != 31 {
+		t.Fatalf("Expected 31 columns, got %d", len(timeline.Columns))
+	}
+
+	total := 0
+	for _, column := range timeline.Columns {
+		total += column.Commits
+	}
+	if total != 2 {
+		t.Errorf("Expected 2 commits in timeline, got %d", total)
+	}
+	if timeline.Columns[2].Commits != 1 {
+		t.Errorf("Expected commit on 2024-01-03, got %d", timeline.Columns[2].Commits)
+	}
+}
+
+func TestOutputMarkdown(t *testing.T) {
+	r := &Reporter{}
+	outputFile := filepath.Join(t.TempDir(), "report.md")
+
+	if err := r.OutputReport(testReport(), outputFile, "markdown"); err != nil {
+		t.Fatalf("Failed to write Markdown report: %v", err)
+	}
+
+	data, err := os.ReadFile(outputFile)
+	if err != nil {
+		t.Fatalf("Failed to read Markdown report: %v", err)
+	}
+	result := string(data)
+
+	for _, expected := range []string{
+		"# GitHub Activity Report: owner",
+		"| 1 | John Doe ([@johndoe](https://github.com/johndoe)) | 1 | +10 | -5 | 1 |",
+		"| owner/repo1 | 2 | +13 | -6 | 2 |",
+		"<details>\n<summary><strong>Jane Smith</strong> (@janesmith) — 1 commit, +3/-1</summary>",
+	} {
+		if !strings.Contains(result, expected) {
+			t.Errorf("Markdown output should contain %q, got:\n%s", expected, result)
+		}
+	}
+}
+
+func TestMarkdownEscape(t *testing.T) {
+	if escaped := markdownEscape("a|b <c>"); escaped != `a\|b &lt;c&gt;` {
+		t.Errorf("Unexpected escaping: %s", escaped)
+	}
+}
+
+func TestOutputOpenMetrics(t *testing.T) {
+	r := &Reporter{}
+	report := testReport()
+	report.Target = `org "quoted"`
+	outputFile := filepath.Join(t.TempDir(), "report.prom")
+
+	if err := r.OutputReport(report, outputFile, "openmetrics"); err != nil {
+		t.Fatalf("Failed to write OpenMetrics output: %v", err)
+	}
+
+	data, err := os.ReadFile(outputFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := string(data)
+
+	for _, expected := range []string{
+		"# TYPE ghreporting_contributor_commits gauge\n",
+		`ghreporting_contributor_additions{target="org \"quoted\"",contributor="johndoe",repo="owner/repo1"} 10`,
+		`ghreporting_repository_commits{target="org \"quoted\"",repo="owner/repo1"} 2`,
+		`ghreporting_repository_contributors{target="org \"quoted\"",repo="owner/repo1"} 2`,
+		`ghreporting_report_period_start_seconds{target="org \"quoted\""} 1704067200`,
+	} {
+		if !strings.Contains(result, expected) {
+			t.Errorf("OpenMetrics output should contain %q, got:\n%s", expected, result)
+		}
+	}
+	if !strings.HasSuffix(result, "# EOF\n") {
+		t.Error("OpenMetrics output should end with # EOF")
+	}
+}
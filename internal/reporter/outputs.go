@@ -0,0 +1,164 @@
+package reporter
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"ghreporting/internal/models"
+)
+
+// OutputTarget is one format/destination pair a report is rendered to.
+// An empty File (or "-") writes to stdout.
+type OutputTarget struct {
+	Format string
+	File   string
+}
+
+// Formats that write binary data or several files and therefore need a destination path
+var fileOnlyFormats = map[string]bool{"sqlite": true, "parquet": true, "xlsx": true}
+
+var supportedFormats = map[string]bool{
+	"text": true, "json": true, "csv": true, "html": true, "markdown": true, "md": true,
+	"template": true, "sqlite": true, "commits-csv": true, "commits-ndjson": true,
+	"parquet": true, "openmetrics": true, "prometheus": true, "xlsx": true, "slack": true,
+}
+
+// Content types of the formats WriteReport can render
+var formatContentTypes = map[string]string{
+	"text":           "text/plain; charset=utf-8",
+	"json":           "application/json",
+	"csv":            "text/csv; charset=utf-8",
+	"html":           "text/html; charset=utf-8",
+	"markdown":       "text/markdown; charset=utf-8",
+	"md":             "text/markdown; charset=utf-8",
+	"template":       "text/plain; charset=utf-8",
+	"sqlite":         "application/vnd.sqlite3",
+	"commits-csv":    "text/csv; charset=utf-8",
+	"commits-ndjson": "application/x-ndjson",
+	"openmetrics":    "application/openmetrics-text; version=1.0.0; charset=utf-8",
+	"prometheus":     "text/plain; version=0.0.4; charset=utf-8",
+	"xlsx":           "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	"slack":          "application/json",
+}
+
+// ContentType returns the MIME type of a format rendered by WriteReport, or false for
+// formats that cannot be streamed (parquet writes a directory tree)
+func ContentType(format string) (string, bool) {
+	contentType, ok := formatContentTypes[format]
+	return contentType, ok
+}
+
+// WriteReport renders the report in the given format to w. The report is rendered
+// completely before anything is written, so a failure leaves w untouched.
+func (r *Reporter) WriteReport(w io.Writer, report *models.Report, format string) error {
+	if _, ok := ContentType(format); !ok {
+		return fmt.Errorf("unsupported output format: %s", format)
+	}
+
+	dir, err := os.MkdirTemp("", "ghreporting-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "report")
+	if err := r.OutputReport(report, path, format); err != nil {
+		return err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = io.Copy(w, file)
+	return err
+}
+
+// ParseOutputTarget parses one format=destination pair. The destination may itself
+// contain "=" and ",", e.g. "parquet=lake/dt=2024".
+func ParseOutputTarget(pair string) (OutputTarget, error) {
+	format, file, found := strings.Cut(strings.TrimSpace(pair), "=")
+	if !found || strings.TrimSpace(format) == "" {
+		return OutputTarget{}, fmt.Errorf("invalid output %q: expected format=destination", pair)
+	}
+	return OutputTarget{Format: strings.TrimSpace(format), File: strings.TrimSpace(file)}, nil
+}
+
+// ParseOutputTargets parses a comma separated list of format=destination pairs,
+// e.g. "text=-,json=report.json,csv=report.csv"
+func ParseOutputTargets(spec string) ([]OutputTarget, error) {
+	var targets []OutputTarget
+	for _, pair := range strings.Split(spec, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		target, err := ParseOutputTarget(pair)
+		if err != nil {
+			return nil, err
+		}
+		targets = append(targets, target)
+	}
+
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("no outputs specified")
+	}
+	return targets, nil
+}
+
+// ValidateOutputTargets checks formats and destinations before any data is fetched
+func (r *Reporter) ValidateOutputTargets(targets []OutputTarget) error {
+	stdout := 0
+	destinations := make(map[string]string)
+	for _, target := range targets {
+		if !supportedFormats[target.Format] {
+			return fmt.Errorf("unsupported output format: %s", target.Format)
+		}
+
+		toStdout := target.File == "" || target.File == "-"
+		if toStdout && fileOnlyFormats[target.Format] {
+			return fmt.Errorf("%s output requires a destination file", target.Format)
+		}
+		if target.Format == "template" {
+			if _, err := r.parseTemplate(); err != nil {
+				return err
+			}
+		}
+		if toStdout {
+			stdout++
+			continue
+		}
+
+		destination := filepath.Clean(target.File)
+		if format, exists := destinations[destination]; exists {
+			return fmt.Errorf("%s and %s outputs are both written to %s", format, target.Format, target.File)
+		}
+		destinations[destination] = target.Format
+	}
+
+	if stdout > 1 {
+		return fmt.Errorf("only one output can be written to stdout")
+	}
+	return nil
+}
+
+// OutputReports renders the report to every target. All targets are attempted
+// even if one fails; the returned error combines every failure.
+func (r *Reporter) OutputReports(report *models.Report, targets []OutputTarget) error {
+	var errs []error
+	for _, target := range targets {
+		if err := r.OutputReport(report, target.File, target.Format); err != nil {
+			destination := target.File
+			if destination == "" || destination == "-" {
+				destination = "stdout"
+			}
+			errs = append(errs, fmt.Errorf("%s output to %s: %w", target.Format, destination, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,92 @@
+package reporter
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseOutputTargets(t *testing.T) {
+	targets, err := ParseOutputTargets("text=-, json=report.json,csv=out/report.csv")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []OutputTarget{
+		{Format: "text", File: "-"},
+		{Format: "json", File: "report.json"},
+		{Format: "csv", File: "out/report.csv"},
+	}
+	if len(targets) != len(expected) {
+		t.Fatalf("Expected %d targets, got %d", len(expected), len(targets))
+	}
+	for i, target := range targets {
+		if target != expected[i] {
+			t.Errorf("Target %d: expected %+v, got %+v", i, expected[i], target)
+		}
+	}
+
+	target, err := ParseOutputTarget("parquet=lake/dt=2024,eu")
+	if err != nil || target.Format != "parquet" || target.File != "lake/dt=2024,eu" {
+		t.Errorf("Expected the destination after the first =, got %+v (%v)", target, err)
+	}
+
+	for _, invalid := range []string{"", "json", "=report.json"} {
+		if _, err := ParseOutputTargets(invalid); err == nil {
+			t.Errorf("Expected error for %q", invalid)
+		}
+	}
+}
+
+func TestValidateOutputTargets(t *testing.T) {
+	r := &Reporter{}
+
+	tests := []struct {
+		name    string
+		targets []OutputTarget
+		valid   bool
+	}{
+		{"single stdout", []OutputTarget{{Format: "text"}}, true},
+		{"stdout and files", []OutputTarget{{Format: "text", File: "-"}, {Format: "xlsx", File: "r.xlsx"}}, true},
+		{"unknown format", []OutputTarget{{Format: "pdf", File: "r.pdf"}}, false},
+		{"binary to stdout", []OutputTarget{{Format: "sqlite", File: "-"}}, false},
+		{"two stdout", []OutputTarget{{Format: "text"}, {Format: "json", File: "-"}}, false},
+		{"template without file", []OutputTarget{{Format: "template", File: "r.txt"}}, false},
+		{"same destination", []OutputTarget{{Format: "json", File: "r.out"}, {Format: "csv", File: "./r.out"}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := r.ValidateOutputTargets(tt.targets)
+			if tt.valid && err != nil {
+				t.Errorf("Expected valid, got %v", err)
+			}
+			if !tt.valid && err == nil {
+				t.Error("Expected validation error")
+			}
+		})
+	}
+}
+
+func TestOutputReports(t *testing.T) {
+	dir := t.TempDir()
+	r := &Reporter{}
+
+	targets := []OutputTarget{
+		{Format: "json", File: filepath.Join(dir, "report.json")},
+		{Format: "csv", File: filepath.Join(dir, "missing", "report.csv")},
+		{Format: "markdown", File: filepath.Join(dir, "report.md")},
+	}
+
+	err := r.OutputReports(testReport(), targets)
+	if err == nil {
+		t.Fatal("Expected error for unwritable destination")
+	}
+
+	// A failing output must not prevent the remaining ones
+	for _, file := range []string{"report.json", "report.md"} {
+		if _, err := os.Stat(filepath.Join(dir, file)); err != nil {
+			t.Errorf("Expected %s to be written: %v", file, err)
+		}
+	}
+}
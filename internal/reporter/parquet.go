@@ -0,0 +1,177 @@
+package reporter
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+
+	"ghreporting/internal/models"
+)
+
+// parquetCommit is the stable schema of the commit-level Parquet table.
+// Columns may be added over time but existing ones are never renamed or retyped.
+type parquetCommit struct {
+	Target      string    `parquet:"target,dict,snappy"`
+	Repository  string    `parquet:"repository,dict,snappy"`
+	Branch      string    `parquet:"branch,dict,snappy"`
+	SHA         string    `parquet:"sha,snappy"`
+	AuthorKey   string    `parquet:"author_key,dict,snappy"`
+	AuthorName  string    `parquet:"author_name,dict,snappy"`
+	AuthorEmail string    `parquet:"author_email,dict,snappy"`
+	AuthorLogin string    `parquet:"author_login,dict,snappy"`
+	AuthoredAt  time.Time `parquet:"authored_at,timestamp(millisecond),snappy"`
+	CommittedAt time.Time `parquet:"committed_at,timestamp(millisecond),snappy"`
+	Additions   int64     `parquet:"additions,snappy"`
+	Deletions   int64     `parquet:"deletions,snappy"`
+	Total       int64     `parquet:"total,snappy"`
+	PullRequest *int64    `parquet:"pull_request,optional,snappy"`
+	Subject     string    `parquet:"subject,snappy"`
+}
+
+// parquetContributor is the stable schema of the contributor-by-repository aggregate table
+type parquetContributor struct {
+	Target     string `parquet:"target,dict,snappy"`
+	AuthorKey  string `parquet:"author_key,dict,snappy"`
+	Name       string `parquet:"name,dict,snappy"`
+	Email      string `parquet:"email,dict,snappy"`
+	Login      string `parquet:"login,dict,snappy"`
+	Repository string `parquet:"repository,dict,snappy"`
+	Commits    int64  `parquet:"commits,snappy"`
+	Additions  int64  `parquet:"additions,snappy"`
+	Deletions  int64  `parquet:"deletions,snappy"`
+}
+
+// parquetTeam is the stable schema of the team-by-repository aggregate table
+type parquetTeam struct {
+	Target     string `parquet:"target,dict,snappy"`
+	Team       string `parquet:"team,dict,snappy"`
+	Repository string `parquet:"repository,dict,snappy"`
+	Commits    int64  `parquet:"commits,snappy"`
+	Additions  int64  `parquet:"additions,snappy"`
+	Deletions  int64  `parquet:"deletions,snappy"`
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// outputParquet writes the commit and contributor tables below outputDir using
+// Hive-style period partitions, so the directory can be copied to object storage:
+//
+//	<outputDir>/commits/period_start=2024-01-01/period_end=2024-01-31/<target>.parquet
+//	<outputDir>/contributors/period_start=2024-01-01/period_end=2024-01-31/<target>.parquet
+//	<outputDir>/teams/period_start=2024-01-01/period_end=2024-01-31/<target>.parquet
+//
+// The teams table is only written when team memberships are configured.
+// Re-running a report for the same target and period replaces its files.
+func (r *Reporter) outputParquet(report *models.Report, outputDir string) error {
+	if outputDir == "" {
+		return fmt.Errorf("parquet output format requires an output directory")
+	}
+
+	var commits []parquetCommit
+	for _, repo := range report.Repositories {
+		for _, branch := range repo.Branches {
+			for _, commit := range branch.Commits {
+				commits = append(commits, r.parquetCommitRow(report.Target, repo.FullName, branch.Name, commit))
+			}
+		}
+	}
+
+	var contributors []parquetContributor
+	for _, key := range r.sortedContributors(report) {
+		stats := report.Summary[key]
+		for _, repoName := range sortedRepositories(stats) {
+			repoStats := stats.Repositories[repoName]
+			contributors = append(contributors, parquetContributor{
+				Target:     report.Target,
+				AuthorKey:  key,
+				Name:       stats.Name,
+				Email:      stats.Email,
+				Login:      stats.Login,
+				Repository: repoName,
+				Commits:    int64(repoStats.Commits),
+				Additions:  int64(repoStats.Additions),
+				Deletions:  int64(repoStats.Deletions),
+			})
+		}
+	}
+
+	var teams []parquetTeam
+	for _, name := range r.sortedTeams(report) {
+		team := report.Teams[name]
+		for _, repoName := range teamRepositories(team) {
+			repoStats := team.Repositories[repoName]
+			teams = append(teams, parquetTeam{
+				Target:     report.Target,
+				Team:       name,
+				Repository: repoName,
+				Commits:    int64(repoStats.Commits),
+				Additions:  int64(repoStats.Additions),
+				Deletions:  int64(repoStats.Deletions),
+			})
+		}
+	}
+
+	if err := writeParquetPartition(outputDir, "commits", report, commits); err != nil {
+		return err
+	}
+	if err := writeParquetPartition(outputDir, "contributors", report, contributors); err != nil {
+		return err
+	}
+	if len(report.Teams) == 0 {
+		return nil
+	}
+	return writeParquetPartition(outputDir, "teams", report, teams)
+}
+
+func (r *Reporter) parquetCommitRow(target, repo, branch string, commit models.Commit) parquetCommit {
+	record := NewCommitRecord(repo, branch, commit)
+	row := parquetCommit{
+		Target:      target,
+		Repository:  repo,
+		Branch:      branch,
+		SHA:         commit.SHA,
+		AuthorKey:   r.getAuthorKey(commit.Author),
+		AuthorName:  record.AuthorName,
+		AuthorEmail: record.AuthorEmail,
+		AuthorLogin: record.AuthorLogin,
+		AuthoredAt:  commit.Date,
+		CommittedAt: commit.CommitDate,
+		Additions:   int64(commit.Stats.Additions),
+		Deletions:   int64(commit.Stats.Deletions),
+		Total:       int64(commit.Stats.Total),
+		Subject:     record.Subject,
+	}
+	if row.CommittedAt.IsZero() {
+		row.CommittedAt = commit.Date
+	}
+	if commit.PullRequest != nil {
+		number := int64(commit.PullRequest.Number)
+		row.PullRequest = &number
+	}
+	return row
+}
+
+func writeParquetPartition[T any](outputDir, table string, report *models.Report, rows []T) error {
+	dir := filepath.Join(outputDir, table,
+		"period_start="+report.Period.Since.Format("2006-01-02"),
+		"period_end="+report.Period.Until.Format("2006-01-02"))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create partition directory: %w", err)
+	}
+
+	name := strings.Trim(unsafeFileChars.ReplaceAllString(report.Target, "_"), "_")
+	if name == "" {
+		name = "report"
+	}
+
+	path := filepath.Join(dir, name+".parquet")
+	if err := parquet.WriteFile(path, rows); err != nil {
+		return fmt.Errorf("failed to write %s table: %w", table, err)
+	}
+	return nil
+}
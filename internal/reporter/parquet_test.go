@@ -0,0 +1,54 @@
+package reporter
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/parquet-go/parquet-go"
+
+	"ghreporting/internal/models"
+)
+
+func TestOutputParquet(t *testing.T) {
+	outputDir := t.TempDir()
+	report := testReport()
+	report.Repositories[0].Branches[0].Commits[0].PullRequest = &models.PullRequest{Number: 7}
+
+	r := &Reporter{}
+	if err := r.OutputReport(report, outputDir, "parquet"); err != nil {
+		t.Fatalf("Failed to write Parquet output: %v", err)
+	}
+
+	partition := filepath.Join("period_start=2024-01-01", "period_end=2024-01-31", "owner.parquet")
+
+	commits, err := parquet.ReadFile[parquetCommit](filepath.Join(outputDir, "commits", partition))
+	if err != nil {
+		t.Fatalf("Failed to read commits table: %v", err)
+	}
+	if len(commits) != 2 {
+		t.Fatalf("Expected 2 commit rows, got %d", len(commits))
+	}
+	if commits[0].SHA != "abc123" || commits[0].Additions != 10 || commits[0].Subject != "Add feature" {
+		t.Errorf("Unexpected commit row: %+v", commits[0])
+	}
+	if commits[0].PullRequest == nil || *commits[0].PullRequest != 7 {
+		t.Errorf("Expected pull request 7, got %v", commits[0].PullRequest)
+	}
+	if commits[1].PullRequest != nil {
+		t.Errorf("Expected no pull request, got %d", *commits[1].PullRequest)
+	}
+	if !commits[0].AuthoredAt.Equal(report.Repositories[0].Branches[0].Commits[0].Date) {
+		t.Errorf("Unexpected authored_at: %v", commits[0].AuthoredAt)
+	}
+
+	contributors, err := parquet.ReadFile[parquetContributor](filepath.Join(outputDir, "contributors", partition))
+	if err != nil {
+		t.Fatalf("Failed to read contributors table: %v", err)
+	}
+	if len(contributors) != 2 {
+		t.Fatalf("Expected 2 contributor rows, got %d", len(contributors))
+	}
+	if contributors[0].Login != "johndoe" || contributors[0].Commits != 1 || contributors[0].Repository != "owner/repo1" {
+		t.Errorf("Unexpected contributor row: %+v", contributors[0])
+	}
+}
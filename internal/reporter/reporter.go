@@ -4,8 +4,9 @@ import (
 	"context"
 	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
 	"sort"
 	"strings"
@@ -14,19 +15,43 @@ import (
 
 	"ghreporting/internal/client"
 	"ghreporting/internal/models"
+	"ghreporting/internal/progress"
 )
 
 // Reporter handles report generation
 type Reporter struct {
-	client      *client.GitHubClient
-	allBranches bool
+	client        *client.GitHubClient
+	allBranches   bool
+	coAuthors     bool
+	coAuthorSplit CoAuthorSplit
+	attribution   IdentitySource
+	periodBasis   IdentitySource
+	resolvePRs    bool
+	prAttribution bool
+	templatePath  string
+	sortOrder     string
+	runID         string
+	commitStream  *CommitWriter
+	baseline      *baseline
+	repoWorkers   int
+	branchWorkers int
+	progress      *progress.Tracker
+	teams         map[string][]string // Team name to members
+	githubTeams   bool
 }
 
 // NewReporter creates a new reporter instance
 func NewReporter(client *client.GitHubClient) *Reporter {
 	return &Reporter{
-		client:      client,
-		allBranches: false, // Default to analyzing only important branches
+		client:        client,
+		allBranches:   false, // Default to analyzing only important branches
+		coAuthors:     true,
+		coAuthorSplit: CoAuthorSplitAuthor,
+		attribution:   IdentityAuthor,
+		periodBasis:   IdentityCommitter, // Matches the date the GitHub API filters on
+		sortOrder:     "changes",
+		repoWorkers:   10,
+		branchWorkers: 1,
 	}
 }
 
@@ -35,9 +60,54 @@ func (r *Reporter) SetAllBranches(allBranches bool) {
 	r.allBranches = allBranches
 }
 
+// SetCoAuthors configures whether Co-authored-by trailers are credited in the summary
+func (r *Reporter) SetCoAuthors(enabled bool) {
+	r.coAuthors = enabled
+}
+
+// SetCoAuthorSplit configures how line changes are shared between co-authors
+func (r *Reporter) SetCoAuthorSplit(split CoAuthorSplit) {
+	r.coAuthorSplit = split
+}
+
+// SetAttribution configures whether commits are credited to their author or committer
+func (r *Reporter) SetAttribution(source IdentitySource) {
+	r.attribution = source
+}
+
+// SetPeriodBasis configures whether the author date or commit date decides if a commit is in the period
+func (r *Reporter) SetPeriodBasis(source IdentitySource) {
+	r.periodBasis = source
+}
+
+// SetResolvePullRequests configures whether default branch commits are linked to their pull request
+func (r *Reporter) SetResolvePullRequests(resolve bool) {
+	r.resolvePRs = resolve
+}
+
+// SetPullRequestAttribution configures whether default branch commits are credited to
+// the author of their pull request. Enabling it also resolves pull requests.
+func (r *Reporter) SetPullRequestAttribution(enabled bool) {
+	r.prAttribution = enabled
+}
+
+// SetConcurrency configures how many repositories are processed at once and how many
+// branches of each repository are fetched at once. Commit detail concurrency, the cap
+// on API requests in flight and the adaptive mode are configured on the client.
+func (r *Reporter) SetConcurrency(repositories, branches int) {
+	r.repoWorkers = repositories
+	r.branchWorkers = branches
+}
+
+// SetProgress configures the tracker updated while a report is generated
+func (r *Reporter) SetProgress(tracker *progress.Tracker) {
+	r.progress = tracker
+}
+
 // GenerateReport generates a comprehensive report for the given target
 func (r *Reporter) GenerateReport(ctx context.Context, target string, since, until time.Time) (*models.Report, error) {
-	log.Printf("Generating report for %s from %s to %s", target, since.Format("2006-01-02"), until.Format("2006-01-02"))
+	start := time.Now()
+	slog.Info("Generating report", "target", target, "since", since.Format("2006-01-02"), "until", until.Format("2006-01-02"))
 
 	// Get all repositories
 	repos, err := r.client.ListRepositories(ctx, target)
@@ -45,7 +115,8 @@ func (r *Reporter) GenerateReport(ctx context.Context, target string, since, unt
 		return nil, fmt.Errorf("failed to list repositories: %w", err)
 	}
 
-	log.Printf("Found %d repositories", len(repos))
+	slog.Info("Found repositories", "target", target, "repositories", len(repos))
+	r.progress.SetRepositories(len(repos))
 
 	// Process repositories concurrently
 	reposChan := make(chan models.Repository, len(repos))
@@ -53,12 +124,12 @@ func (r *Reporter) GenerateReport(ctx context.Context, target string, since, unt
 	errorsChan := make(chan error, len(repos))
 
 	// Worker pool for processing repositories
-	const maxWorkers = 10
+	diagnostics := &diagnosticLog{}
 	var wg sync.WaitGroup
 
-	for i := 0; i < maxWorkers && i < len(repos); i++ {
+	for i := 0; i < max(r.repoWorkers, 1) && i < len(repos); i++ {
 		wg.Add(1)
-		go r.processRepositoryWorker(ctx, reposChan, resultsChan, errorsChan, since, until, &wg)
+		go r.processRepositoryWorker(ctx, diagnostics, reposChan, resultsChan, errorsChan, since, until, &wg)
 	}
 
 	// Send repositories to workers
@@ -77,7 +148,8 @@ func (r *Reporter) GenerateReport(ctx context.Context, target string, since, unt
 	}()
 
 	var processedRepos []models.Repository
-	var errors []error
+	var errs []error
+	var skipped []string
 
 	// Collect results and errors
 	done := false
@@ -90,48 +162,113 @@ func (r *Reporter) GenerateReport(ctx context.Context, target string, since, unt
 				processedRepos = append(processedRepos, repo)
 			}
 		case err, ok := <-errorsChan:
-			if !ok {
+			var repoErr *repositoryError
+			switch {
+			case !ok:
 				errorsChan = nil
-			} else {
-				errors = append(errors, err)
+			case ctx.Err() != nil && errors.Is(err, ctx.Err()) && errors.As(err, &repoErr):
+				// Cancelled before the repository was finished
+				skipped = append(skipped, repoErr.repo)
+			default:
+				errs = append(errs, err)
 			}
 		}
 		done = resultsChan == nil && errorsChan == nil
 	}
 
-	// Log errors but continue
-	for _, err := range errors {
-		log.Printf("Warning: %v", err)
+	// Log errors but continue; they are listed in the report diagnostics
+	for _, err := range errs {
+		var repoErr *repositoryError
+		if errors.As(err, &repoErr) {
+			slog.Warn("Failed to process repository", "repo", repoErr.repo, "error", repoErr.err)
+			diagnostics.add(StageRepository, repoErr.repo, "", "", repoErr.err)
+		}
 	}
 
-	log.Printf("Successfully processed %d repositories", len(processedRepos))
+	slog.Info("Processed repositories", "target", target, "repositories", len(processedRepos), "failed", len(errs), "duration", time.Since(start))
 
 	// Generate summary statistics
 	summary := r.generateSummary(processedRepos)
 
-	return &models.Report{
+	report := &models.Report{
 		Target:       target,
 		Period:       models.Period{Since: since, Until: until},
 		Repositories: processedRepos,
 		Summary:      summary,
-	}, nil
+		Teams:        r.generateTeams(processedRepos, r.reportTeams(ctx, target, diagnostics)),
+	}
+
+	// An interrupted run still returns what was finished, marked as incomplete
+	if ctx.Err() != nil {
+		cause := context.Cause(ctx)
+		slog.Warn("Report interrupted", "target", target, "reason", cause, "skipped", len(skipped))
+		report.Incomplete = newIncomplete(cause.Error(), processedRepos, skipped)
+	}
+
+	report.Diagnostics = diagnostics.report(skipped)
+	if len(report.Diagnostics) > 0 {
+		slog.Warn("Data was skipped, see the report diagnostics", "target", target, "diagnostics", len(report.Diagnostics))
+	}
+
+	return report, nil
+}
+
+// repositoryError is a failure to process one repository
+type repositoryError struct {
+	repo string
+	err  error
+}
+
+func (e *repositoryError) Error() string {
+	return fmt.Sprintf("repository %s: %v", e.repo, e.err)
 }
 
-func (r *Reporter) processRepositoryWorker(ctx context.Context, reposChan <-chan models.Repository, resultsChan chan<- models.Repository, errorsChan chan<- error, since, until time.Time, wg *sync.WaitGroup) {
+func (e *repositoryError) Unwrap() error {
+	return e.err
+}
+
+// newIncomplete describes an interrupted report from the repositories it finished and skipped
+func newIncomplete(reason string, finished []models.Repository, skipped []string) *models.Incomplete {
+	incomplete := &models.Incomplete{Reason: reason, SkippedRepositories: skipped}
+	for _, repo := range finished {
+		incomplete.FinishedRepositories = append(incomplete.FinishedRepositories, repo.FullName)
+	}
+	sort.Strings(incomplete.FinishedRepositories)
+	sort.Strings(incomplete.SkippedRepositories)
+	return incomplete
+}
+
+// incompleteNotice returns a one-line warning for interrupted reports, or "" when complete
+func incompleteNotice(report *models.Report) string {
+	if report.Incomplete == nil {
+		return ""
+	}
+	finished, skipped := len(report.Incomplete.FinishedRepositories), len(report.Incomplete.SkippedRepositories)
+	return fmt.Sprintf("incomplete report: interrupted (%s) after %d of %d repositories",
+		report.Incomplete.Reason, finished, finished+skipped)
+}
+
+func (r *Reporter) processRepositoryWorker(ctx context.Context, diagnostics *diagnosticLog, reposChan <-chan models.Repository, resultsChan chan<- models.Repository, errorsChan chan<- error, since, until time.Time, wg *sync.WaitGroup) {
 	defer wg.Done()
 
 	for repo := range reposChan {
-		processedRepo, err := r.processRepository(ctx, repo, since, until)
+		if err := ctx.Err(); err != nil {
+			errorsChan <- &repositoryError{repo: repo.FullName, err: err}
+			continue
+		}
+		processedRepo, err := r.processRepository(ctx, repo, since, until, diagnostics)
+		r.progress.RepositoryDone()
 		if err != nil {
-			errorsChan <- fmt.Errorf("repository %s: %w", repo.FullName, err)
+			errorsChan <- &repositoryError{repo: repo.FullName, err: err}
 			continue
 		}
 		resultsChan <- processedRepo
 	}
 }
 
-func (r *Reporter) processRepository(ctx context.Context, repo models.Repository, since, until time.Time) (models.Repository, error) {
-	log.Printf("Processing repository: %s", repo.FullName)
+func (r *Reporter) processRepository(ctx context.Context, repo models.Repository, since, until time.Time, diagnostics *diagnosticLog) (models.Repository, error) {
+	start := time.Now()
+	slog.Debug("Processing repository", "repo", repo.FullName)
 
 	// Parse owner and repo from full name
 	parts := strings.Split(repo.FullName, "/")
@@ -149,27 +286,96 @@ func (r *Reporter) processRepository(ctx context.Context, repo models.Repository
 	// Process only a subset of important branches to avoid rate limits
 	branchesToProcess := r.selectBranchesToProcess(branches, repo.DefaultBranch)
 
+	// Fetch branches concurrently, keeping their order in the report
+	limiter := client.NewLimiter(r.branchWorkers, nil)
+	results := make([]*models.Branch, len(branchesToProcess))
+	var wg sync.WaitGroup
+	for i, branch := range branchesToProcess {
+		if err := limiter.Acquire(ctx); err != nil {
+			slog.Warn("Failed to get commits", "repo", repo.FullName, "branch", branch.Name, "error", err)
+			continue
+		}
+		wg.Add(1)
+		go func(i int, branch models.Branch) {
+			defer wg.Done()
+			defer limiter.Release()
+			results[i] = r.processBranch(ctx, repo, owner, repoName, branch, since, until, diagnostics)
+		}(i, branch)
+	}
+	wg.Wait()
+
+	// A repository interrupted part way through is skipped rather than reported short
+	if err := ctx.Err(); err != nil {
+		return repo, err
+	}
+
 	var processedBranches []models.Branch
-	for _, branch := range branchesToProcess {
-		commits, err := r.client.ListCommits(ctx, owner, repoName, branch.Name, since, until)
-		if err != nil {
-			log.Printf("Warning: failed to get commits for %s@%s: %v", repo.FullName, branch.Name, err)
+	for i, branch := range results {
+		if branch == nil {
 			continue
 		}
+		processedBranches = append(processedBranches, *branch)
 
-		branch.Commits = commits
-		processedBranches = append(processedBranches, branch)
-		log.Printf("  Branch %s: %d commits", branch.Name, len(commits))
+		// Commits are streamed once their repository is kept in the report, so the
+		// export matches it. Unchanged branches were exported by an earlier run.
+		if r.commitStream != nil && !r.baseline.unchanged(repo.FullName, branchesToProcess[i]) {
+			if err := r.commitStream.WriteBranch(repo.FullName, *branch); err != nil {
+				slog.Warn("Failed to stream commits", "repo", repo.FullName, "branch", branch.Name, "error", err)
+			}
+		}
 	}
 
 	repo.Branches = processedBranches
+	slog.Debug("Processed repository", "repo", repo.FullName, "branches", len(processedBranches), "duration", time.Since(start))
 	return repo, nil
 }
 
+// processBranch fetches the commits of one branch, returning nil when they could not be listed
+func (r *Reporter) processBranch(ctx context.Context, repo models.Repository, owner, repoName string, branch models.Branch, since, until time.Time, diagnostics *diagnosticLog) *models.Branch {
+	if r.baseline.unchanged(repo.FullName, branch) {
+		slog.Info("Branch unchanged since previous report", "repo", repo.FullName, "branch", branch.Name)
+		return &branch
+	}
+
+	start := time.Now()
+	commits, failed, err := r.listCommits(ctx, owner, repoName, branch.Name, since, until)
+	if err != nil {
+		slog.Warn("Failed to get commits", "repo", repo.FullName, "branch", branch.Name, "error", err)
+		diagnostics.add(StageBranch, repo.FullName, branch.Name, "", err)
+		return nil
+	}
+	for _, failure := range failed {
+		diagnostics.add(StageCommit, repo.FullName, branch.Name, failure.SHA, failure.Err)
+	}
+
+	if branch.Name == repo.DefaultBranch && (r.resolvePRs || r.prAttribution) {
+		r.resolvePullRequests(ctx, repo.FullName, branch.Name, commits, diagnostics)
+	}
+
+	branch.Commits = commits
+	r.progress.AddCommits(len(commits))
+	slog.Info("Fetched branch", "repo", repo.FullName, "branch", branch.Name, "commits", len(commits), "duration", time.Since(start))
+	return &branch
+}
+
+// resolvePullRequests links each commit to the pull request it was merged through
+func (r *Reporter) resolvePullRequests(ctx context.Context, fullName, branch string, commits []models.Commit, diagnostics *diagnosticLog) {
+	owner, repoName, _ := strings.Cut(fullName, "/")
+	for i := range commits {
+		pr, err := r.client.FindPullRequest(ctx, owner, repoName, branch, commits[i].SHA)
+		if err != nil {
+			slog.Warn("Failed to resolve pull request", "repo", fullName, "branch", branch, "sha", commits[i].SHA, "error", err)
+			diagnostics.add(StagePullRequest, fullName, branch, commits[i].SHA, err)
+			continue
+		}
+		commits[i].PullRequest = pr
+	}
+}
+
 func (r *Reporter) selectBranchesToProcess(branches []models.Branch, defaultBranch string) []models.Branch {
 	// If allBranches is enabled, return all branches
 	if r.allBranches {
-		log.Printf("Analyzing all %d branches", len(branches))
+		slog.Debug("Analyzing all branches", "branches", len(branches))
 		return branches
 	}
 
@@ -200,7 +406,7 @@ func (r *Reporter) selectBranchesToProcess(branches []models.Branch, defaultBran
 		}
 	}
 
-	log.Printf("Analyzing %d important branches out of %d total branches", len(selected), len(branches))
+	slog.Debug("Analyzing important branches", "selected", len(selected), "branches", len(branches))
 	return selected
 }
 
@@ -210,36 +416,49 @@ func (r *Reporter) generateSummary(repos []models.Repository) map[string]models.
 	for _, repo := range repos {
 		for _, branch := range repo.Branches {
 			for _, commit := range branch.Commits {
-				authorKey := r.getAuthorKey(commit.Author)
-
-				stats, exists := summary[authorKey]
-				if !exists {
-					stats = models.ContributorStats{
-						Name:         commit.Author.Name,
-						Email:        commit.Author.Email,
-						Login:        commit.Author.Login,
-						Repositories: make(map[string]models.RepositoryStats),
-					}
+				for _, credit := range r.commitCredits(commit) {
+					r.addContribution(summary, repo.FullName, credit)
 				}
+			}
+		}
+	}
 
-				// Update global stats
-				stats.TotalCommits++
-				stats.TotalAdditions += commit.Stats.Additions
-				stats.TotalDeletions += commit.Stats.Deletions
+	return summary
+}
 
-				// Update repository-specific stats
-				repoStats := stats.Repositories[repo.FullName]
-				repoStats.Commits++
-				repoStats.Additions += commit.Stats.Additions
-				repoStats.Deletions += commit.Stats.Deletions
-				stats.Repositories[repo.FullName] = repoStats
+func (r *Reporter) addContribution(summary map[string]models.ContributorStats, repoName string, credit commitCredit) {
+	authorKey := r.getAuthorKey(credit.author)
 
-				summary[authorKey] = stats
-			}
+	stats, exists := summary[authorKey]
+	if !exists {
+		stats = models.ContributorStats{
+			Name:         credit.author.Name,
+			Email:        credit.author.Email,
+			Login:        credit.author.Login,
+			Repositories: make(map[string]models.RepositoryStats),
 		}
 	}
+	// Pull request authors are only known by login; their own commits fill in the rest
+	if stats.Name == "" {
+		stats.Name = credit.author.Name
+	}
+	if stats.Email == "" {
+		stats.Email = credit.author.Email
+	}
 
-	return summary
+	// Update global stats
+	stats.TotalCommits++
+	stats.TotalAdditions += credit.additions
+	stats.TotalDeletions += credit.deletions
+
+	// Update repository-specific stats
+	repoStats := stats.Repositories[repoName]
+	repoStats.Commits++
+	repoStats.Additions += credit.additions
+	repoStats.Deletions += credit.deletions
+	stats.Repositories[repoName] = repoStats
+
+	summary[authorKey] = stats
 }
 
 func (r *Reporter) getAuthorKey(author models.Author) string {
@@ -253,8 +472,12 @@ func (r *Reporter) getAuthorKey(author models.Author) string {
 	return author.Name
 }
 
-// OutputReport outputs the report in the specified format
+// OutputReport outputs the report in the specified format. An empty outputFile or "-" writes to stdout.
 func (r *Reporter) OutputReport(report *models.Report, outputFile, format string) error {
+	if outputFile == "-" {
+		outputFile = ""
+	}
+
 	switch format {
 	case "json":
 		return r.outputJSON(report, outputFile)
@@ -262,6 +485,26 @@ func (r *Reporter) OutputReport(report *models.Report, outputFile, format string
 		return r.outputCSV(report, outputFile)
 	case "text":
 		return r.outputText(report, outputFile)
+	case "html":
+		return r.outputHTML(report, outputFile)
+	case "markdown", "md":
+		return r.outputMarkdown(report, outputFile)
+	case "template":
+		return r.outputTemplate(report, outputFile)
+	case "sqlite":
+		return r.outputSQLite(report, outputFile)
+	case "commits-csv":
+		return r.outputCommits(report, outputFile, "csv")
+	case "commits-ndjson":
+		return r.outputCommits(report, outputFile, "ndjson")
+	case "parquet":
+		return r.outputParquet(report, outputFile)
+	case "openmetrics", "prometheus":
+		return r.outputOpenMetrics(report, outputFile)
+	case "xlsx":
+		return r.outputXLSX(report, outputFile)
+	case "slack":
+		return r.outputSlack(report, outputFile)
 	default:
 		return fmt.Errorf("unsupported output format: %s", format)
 	}
@@ -282,40 +525,32 @@ func (r *Reporter) outputJSON(report *models.Report, outputFile string) error {
 }
 
 func (r *Reporter) outputCSV(report *models.Report, outputFile string) error {
-	var output *os.File = os.Stdout
-	if outputFile != "" {
-		var err error
-		output, err = os.Create(outputFile)
-		if err != nil {
-			return err
-		}
-		defer output.Close()
+	output, err := openOutput(outputFile)
+	if err != nil {
+		return err
 	}
+	defer output.Close()
 
 	writer := csv.NewWriter(output)
 	defer writer.Flush()
 
 	// Write header
 	header := []string{"Author", "Login", "Email", "Repository", "Commits", "Additions", "Deletions"}
+	if len(report.Teams) > 0 {
+		header = append(header, "Teams")
+	}
 	if err := writer.Write(header); err != nil {
 		return err
 	}
 
 	// Sort contributors by total contributions
-	var contributors []string
-	for contributor := range report.Summary {
-		contributors = append(contributors, contributor)
-	}
-	sort.Slice(contributors, func(i, j int) bool {
-		a := report.Summary[contributors[i]]
-		b := report.Summary[contributors[j]]
-		return (a.TotalAdditions + a.TotalDeletions) > (b.TotalAdditions + b.TotalDeletions)
-	})
+	contributors := r.sortedContributors(report)
 
 	// Write data
 	for _, contributor := range contributors {
 		stats := report.Summary[contributor]
-		for repoName, repoStats := range stats.Repositories {
+		for _, repoName := range sortedRepositories(stats) {
+			repoStats := stats.Repositories[repoName]
 			record := []string{
 				stats.Name,
 				stats.Login,
@@ -325,6 +560,9 @@ func (r *Reporter) outputCSV(report *models.Report, outputFile string) error {
 				fmt.Sprintf("%d", repoStats.Additions),
 				fmt.Sprintf("%d", repoStats.Deletions),
 			}
+			if len(report.Teams) > 0 {
+				record = append(record, strings.Join(contributorTeams(report, contributor), ";"))
+			}
 			if err := writer.Write(record); err != nil {
 				return err
 			}
@@ -335,31 +573,23 @@ func (r *Reporter) outputCSV(report *models.Report, outputFile string) error {
 }
 
 func (r *Reporter) outputText(report *models.Report, outputFile string) error {
-	var output *os.File = os.Stdout
-	if outputFile != "" {
-		var err error
-		output, err = os.Create(outputFile)
-		if err != nil {
-			return err
-		}
-		defer output.Close()
+	output, err := openOutput(outputFile)
+	if err != nil {
+		return err
 	}
+	defer output.Close()
 
 	// Print header
 	fmt.Fprintf(output, "GitHub Activity Report for: %s\n", report.Target)
 	fmt.Fprintf(output, "Period: %s to %s\n", report.Period.Since.Format("2006-01-02"), report.Period.Until.Format("2006-01-02"))
 	fmt.Fprintf(output, "Repositories analyzed: %d\n\n", len(report.Repositories))
+	if notice := incompleteNotice(report); notice != "" {
+		fmt.Fprintf(output, "WARNING: %s\n", notice)
+		fmt.Fprintf(output, "Skipped: %s\n\n", strings.Join(report.Incomplete.SkippedRepositories, ", "))
+	}
 
 	// Sort contributors by total contributions
-	var contributors []string
-	for contributor := range report.Summary {
-		contributors = append(contributors, contributor)
-	}
-	sort.Slice(contributors, func(i, j int) bool {
-		a := report.Summary[contributors[i]]
-		b := report.Summary[contributors[j]]
-		return (a.TotalAdditions + a.TotalDeletions) > (b.TotalAdditions + b.TotalDeletions)
-	})
+	contributors := r.sortedContributors(report)
 
 	// Print summary
 	fmt.Fprintf(output, "CONTRIBUTOR SUMMARY\n")
@@ -381,15 +611,7 @@ func (r *Reporter) outputText(report *models.Report, outputFile string) error {
 		fmt.Fprintf(output, "  Repositories: %d\n", len(stats.Repositories))
 
 		// Show top repositories for this contributor
-		var repoNames []string
-		for repoName := range stats.Repositories {
-			repoNames = append(repoNames, repoName)
-		}
-		sort.Slice(repoNames, func(i, j int) bool {
-			a := stats.Repositories[repoNames[i]]
-			b := stats.Repositories[repoNames[j]]
-			return (a.Additions + a.Deletions) > (b.Additions + b.Deletions)
-		})
+		repoNames := sortedRepositories(stats)
 
 		fmt.Fprintf(output, "  Top Repositories:\n")
 		for i, repoName := range repoNames {
@@ -403,5 +625,36 @@ func (r *Reporter) outputText(report *models.Report, outputFile string) error {
 		fmt.Fprintf(output, "\n")
 	}
 
+	if len(report.Teams) > 0 {
+		fmt.Fprintf(output, "TEAM SUMMARY\n")
+		fmt.Fprintf(output, "============\n\n")
+
+		for _, name := range r.sortedTeams(report) {
+			team := report.Teams[name]
+			fmt.Fprintf(output, "%s\n", team.Name)
+			fmt.Fprintf(output, "  Active Members: %d of %d\n", len(team.Contributors), team.MemberCount)
+			fmt.Fprintf(output, "  Total Commits: %d\n", team.TotalCommits)
+			fmt.Fprintf(output, "  Total Additions: %d\n", team.TotalAdditions)
+			fmt.Fprintf(output, "  Total Deletions: %d\n", team.TotalDeletions)
+			fmt.Fprintf(output, "  Repositories: %d\n", len(team.Repositories))
+			for _, repoName := range teamRepositories(team) {
+				repoStats := team.Repositories[repoName]
+				fmt.Fprintf(output, "    - %s: %d commits (+%d/-%d)\n",
+					repoName, repoStats.Commits, repoStats.Additions, repoStats.Deletions)
+			}
+			fmt.Fprintf(output, "\n")
+		}
+	}
+
+	if len(report.Diagnostics) > 0 {
+		fmt.Fprintf(output, "DIAGNOSTICS\n")
+		fmt.Fprintf(output, "===========\n\n")
+		fmt.Fprintf(output, "Data could not be fetched for %s, so totals may be understated:\n", plural(len(report.Diagnostics), "item"))
+		for _, entry := range report.Diagnostics {
+			fmt.Fprintf(output, "  - [%s] %s %s: %s\n", entry.Category, entry.Stage, diagnosticLocation(entry), entry.Error)
+		}
+		fmt.Fprintf(output, "\n")
+	}
+
 	return nil
 }
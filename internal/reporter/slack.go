@@ -0,0 +1,144 @@
+package reporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"ghreporting/internal/models"
+)
+
+// Number of contributors listed in a Slack message
+const slackTopContributors = 10
+
+// slackBlock is a Block Kit layout block. Only the fields used by the report are modelled.
+type slackBlock struct {
+	Type     string      `json:"type"`
+	Text     *slackText  `json:"text,omitempty"`
+	Fields   []slackText `json:"fields,omitempty"`
+	Elements []slackText `json:"elements,omitempty"`
+}
+
+type slackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// slackMessage is an incoming webhook payload. Text is the notification fallback.
+type slackMessage struct {
+	Text   string       `json:"text"`
+	Blocks []slackBlock `json:"blocks"`
+}
+
+func (r *Reporter) outputSlack(report *models.Report, outputFile string) error {
+	output, err := openOutput(outputFile)
+	if err != nil {
+		return err
+	}
+	defer output.Close()
+
+	return r.writeSlack(output, report)
+}
+
+// writeSlack renders the report totals, top contributors and teams as a Slack
+// incoming webhook payload using Block Kit
+func (r *Reporter) writeSlack(output io.Writer, report *models.Report) error {
+	period := fmt.Sprintf("%s to %s", report.Period.Since.Format("2006-01-02"), report.Period.Until.Format("2006-01-02"))
+	title := "GitHub Activity Report: " + report.Target
+
+	var totals models.RepositoryStats
+	for _, repo := range report.Repositories {
+		repoTotals := repositoryTotals(repo)
+		totals.Commits += repoTotals.Commits
+		totals.Additions += repoTotals.Additions
+		totals.Deletions += repoTotals.Deletions
+	}
+
+	mrkdwn := func(text string) slackText { return slackText{Type: "mrkdwn", Text: text} }
+	message := slackMessage{
+		Text: fmt.Sprintf("%s (%s)", title, period),
+		Blocks: []slackBlock{
+			{Type: "header", Text: &slackText{Type: "plain_text", Text: truncate(title, 150)}},
+			{Type: "context", Elements: []slackText{mrkdwn(period)}},
+			{Type: "section", Fields: []slackText{
+				mrkdwn(fmt.Sprintf("*Repositories*\n%d", len(report.Repositories))),
+				mrkdwn(fmt.Sprintf("*Contributors*\n%d", len(report.Summary))),
+				mrkdwn(fmt.Sprintf("*Commits*\n%d", totals.Commits)),
+				mrkdwn(fmt.Sprintf("*Lines changed*\n+%d / -%d", totals.Additions, totals.Deletions)),
+			}},
+		},
+	}
+
+	contributors := r.sortedContributors(report)
+	if len(contributors) > 0 {
+		var b strings.Builder
+		fmt.Fprintf(&b, "*Top contributors*")
+		for i, contributor := range contributors {
+			if i >= slackTopContributors {
+				fmt.Fprintf(&b, "\n_and %d more_", len(contributors)-i)
+				break
+			}
+			stats := report.Summary[contributor]
+			fmt.Fprintf(&b, "\n%d. *%s*", i+1, slackEscape(stats.Name))
+			if stats.Login != "" {
+				fmt.Fprintf(&b, " (<https://github.com/%s|@%s>)", stats.Login, stats.Login)
+			}
+			fmt.Fprintf(&b, " — %s, +%d / -%d", plural(stats.TotalCommits, "commit"), stats.TotalAdditions, stats.TotalDeletions)
+		}
+		message.Blocks = append(message.Blocks, slackBlock{Type: "divider"}, slackBlock{Type: "section", Text: &slackText{Type: "mrkdwn", Text: b.String()}})
+	} else {
+		message.Blocks = append(message.Blocks, slackBlock{Type: "section", Text: &slackText{Type: "mrkdwn", Text: "_No contributions in this period._"}})
+	}
+
+	if len(report.Teams) > 0 {
+		teams := r.sortedTeams(report)
+		var b strings.Builder
+		fmt.Fprintf(&b, "*Teams*")
+		for i, name := range teams {
+			if i >= slackTopContributors {
+				fmt.Fprintf(&b, "\n_and %d more_", len(teams)-i)
+				break
+			}
+			team := report.Teams[name]
+			fmt.Fprintf(&b, "\n%d. *%s* — %s, +%d / -%d", i+1, slackEscape(team.Name),
+				plural(team.TotalCommits, "commit"), team.TotalAdditions, team.TotalDeletions)
+			if repos := teamRepositories(team); len(repos) > 0 {
+				fmt.Fprintf(&b, ", mostly in %s", slackEscape(repos[0]))
+			}
+		}
+		message.Blocks = append(message.Blocks, slackBlock{Type: "section", Text: &slackText{Type: "mrkdwn", Text: b.String()}})
+	}
+
+	var warnings []string
+	if notice := incompleteNotice(report); notice != "" {
+		warnings = append(warnings, ":warning: This is an "+slackEscape(notice))
+	}
+	if len(report.Diagnostics) > 0 {
+		warnings = append(warnings, fmt.Sprintf(":warning: Data could not be fetched for %s, so totals may be understated", plural(len(report.Diagnostics), "item")))
+	}
+	if len(warnings) > 0 {
+		message.Blocks = append(message.Blocks, slackBlock{Type: "context", Elements: []slackText{mrkdwn(strings.Join(warnings, "\n"))}})
+	}
+
+	encoder := json.NewEncoder(output)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(message)
+}
+
+var slackEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// slackEscape escapes the characters Slack treats as control sequences in mrkdwn
+func slackEscape(text string) string {
+	return slackEscaper.Replace(text)
+}
+
+// truncate shortens text to at most n runes
+func truncate(text string, n int) string {
+	runes := []rune(text)
+	if len(runes) <= n {
+		return text
+	}
+	return string(runes[:n-1]) + "…"
+}
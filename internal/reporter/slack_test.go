@@ -0,0 +1,66 @@
+package reporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"ghreporting/internal/models"
+)
+
+func TestWriteSlack(t *testing.T) {
+	r := &Reporter{}
+	report := testReport()
+	report.Diagnostics = []models.Diagnostic{{Stage: "branch", Repository: "owner/repo1", Branch: "dev", Category: "not_found", Error: "404"}}
+
+	var buf bytes.Buffer
+	if err := r.writeSlack(&buf, report); err != nil {
+		t.Fatalf("Failed to write Slack payload: %v", err)
+	}
+
+	var message slackMessage
+	if err := json.Unmarshal(buf.Bytes(), &message); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	if message.Text != "GitHub Activity Report: owner (2024-01-01 to 2024-01-31)" {
+		t.Errorf("Unexpected fallback text %q", message.Text)
+	}
+	if message.Blocks[0].Type != "header" {
+		t.Errorf("Expected header block first, got %s", message.Blocks[0].Type)
+	}
+
+	payload := buf.String()
+	for _, want := range []string{
+		`*Commits*\n2`,
+		`+13 / -6`,
+		`1. *John Doe* (<https://github.com/johndoe|@johndoe>) — 1 commit, +10 / -5`,
+		`2. *Jane Smith*`,
+		`:warning: Data could not be fetched for 1 item`,
+	} {
+		if !strings.Contains(payload, want) {
+			t.Errorf("Expected payload to contain %q, got:\n%s", want, payload)
+		}
+	}
+}
+
+func TestWriteSlackTopContributors(t *testing.T) {
+	r := &Reporter{}
+	report := testReport()
+	report.Summary = make(map[string]models.ContributorStats)
+	for _, name := range []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k", "l"} {
+		report.Summary[name] = models.ContributorStats{Name: "<" + name + ">", TotalCommits: 1}
+	}
+
+	var buf bytes.Buffer
+	if err := r.writeSlack(&buf, report); err != nil {
+		t.Fatalf("Failed to write Slack payload: %v", err)
+	}
+	payload := buf.String()
+	if !strings.Contains(payload, "_and 2 more_") {
+		t.Errorf("Expected remaining contributors to be summarised, got:\n%s", payload)
+	}
+	if !strings.Contains(payload, "&lt;a&gt;") {
+		t.Errorf("Expected names to be escaped, got:\n%s", payload)
+	}
+}
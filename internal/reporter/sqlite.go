@@ -0,0 +1,241 @@
+package reporter
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	_ "modernc.org/sqlite" // Pure Go SQLite driver
+
+	"ghreporting/internal/models"
+)
+
+// sqliteSchema creates the normalized report tables. Every row is keyed by run ID
+// so successive runs can be appended to the same database.
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS runs (
+	run_id       TEXT PRIMARY KEY,
+	target       TEXT NOT NULL,
+	period_since TEXT NOT NULL,
+	period_until TEXT NOT NULL,
+	generated_at TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS repositories (
+	run_id         TEXT NOT NULL REFERENCES runs(run_id),
+	full_name      TEXT NOT NULL,
+	name           TEXT NOT NULL,
+	url            TEXT,
+	default_branch TEXT,
+	PRIMARY KEY (run_id, full_name)
+);
+CREATE TABLE IF NOT EXISTS branches (
+	run_id     TEXT NOT NULL REFERENCES runs(run_id),
+	repository TEXT NOT NULL,
+	name       TEXT NOT NULL,
+	sha        TEXT,
+	PRIMARY KEY (run_id, repository, name)
+);
+CREATE TABLE IF NOT EXISTS authors (
+	run_id     TEXT NOT NULL REFERENCES runs(run_id),
+	author_key TEXT NOT NULL,
+	name       TEXT,
+	email      TEXT,
+	login      TEXT,
+	PRIMARY KEY (run_id, author_key)
+);
+CREATE TABLE IF NOT EXISTS commits (
+	run_id        TEXT NOT NULL REFERENCES runs(run_id),
+	repository    TEXT NOT NULL,
+	sha           TEXT NOT NULL,
+	author_key    TEXT NOT NULL,
+	committer_key TEXT,
+	authored_at   TEXT,
+	committed_at  TEXT,
+	message       TEXT,
+	additions     INTEGER NOT NULL,
+	deletions     INTEGER NOT NULL,
+	total         INTEGER NOT NULL,
+	pull_request  INTEGER,
+	PRIMARY KEY (run_id, repository, sha)
+);
+CREATE INDEX IF NOT EXISTS commits_author ON commits (run_id, author_key);
+CREATE TABLE IF NOT EXISTS commit_branches (
+	run_id     TEXT NOT NULL REFERENCES runs(run_id),
+	repository TEXT NOT NULL,
+	sha        TEXT NOT NULL,
+	branch     TEXT NOT NULL,
+	PRIMARY KEY (run_id, repository, sha, branch)
+);
+CREATE TABLE IF NOT EXISTS contributor_stats (
+	run_id     TEXT NOT NULL REFERENCES runs(run_id),
+	author_key TEXT NOT NULL,
+	repository TEXT NOT NULL,
+	commits    INTEGER NOT NULL,
+	additions  INTEGER NOT NULL,
+	deletions  INTEGER NOT NULL,
+	PRIMARY KEY (run_id, author_key, repository)
+);
+CREATE TABLE IF NOT EXISTS team_members (
+	run_id TEXT NOT NULL REFERENCES runs(run_id),
+	team   TEXT NOT NULL,
+	member TEXT NOT NULL,
+	PRIMARY KEY (run_id, team, member)
+);
+CREATE TABLE IF NOT EXISTS team_stats (
+	run_id     TEXT NOT NULL REFERENCES runs(run_id),
+	team       TEXT NOT NULL,
+	repository TEXT NOT NULL,
+	commits    INTEGER NOT NULL,
+	additions  INTEGER NOT NULL,
+	deletions  INTEGER NOT NULL,
+	PRIMARY KEY (run_id, team, repository)
+);
+`
+
+// SetRunID configures the run identifier used when appending to an SQLite database
+func (r *Reporter) SetRunID(runID string) {
+	r.runID = runID
+}
+
+func (r *Reporter) outputSQLite(report *models.Report, outputFile string) error {
+	if outputFile == "" {
+		return fmt.Errorf("sqlite output format requires an output file")
+	}
+
+	db, err := sql.Open("sqlite", outputFile)
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		return fmt.Errorf("failed to create schema: %w", err)
+	}
+
+	runID := r.runID
+	if runID == "" {
+		runID = time.Now().UTC().Format("20060102T150405Z")
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var exists int
+	if err := tx.QueryRow(`SELECT COUNT(*) FROM runs WHERE run_id = ?`, runID).Scan(&exists); err != nil {
+		return err
+	}
+	if exists > 0 {
+		return fmt.Errorf("run %s already exists in %s", runID, outputFile)
+	}
+
+	if err := r.writeSQLiteRun(tx, runID, report); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *Reporter) writeSQLiteRun(tx *sql.Tx, runID string, report *models.Report) error {
+	if _, err := tx.Exec(`INSERT INTO runs (run_id, target, period_since, period_until, generated_at) VALUES (?, ?, ?, ?, ?)`,
+		runID, report.Target, sqliteTime(report.Period.Since), sqliteTime(report.Period.Until), sqliteTime(time.Now())); err != nil {
+		return fmt.Errorf("failed to insert run: %w", err)
+	}
+
+	authors := make(map[string]models.Author)
+	addAuthor := func(author models.Author) string {
+		key := r.getAuthorKey(author)
+		if _, exists := authors[key]; !exists {
+			authors[key] = author
+		}
+		return key
+	}
+
+	for _, repo := range report.Repositories {
+		if _, err := tx.Exec(`INSERT INTO repositories (run_id, full_name, name, url, default_branch) VALUES (?, ?, ?, ?, ?)`,
+			runID, repo.FullName, repo.Name, repo.URL, repo.DefaultBranch); err != nil {
+			return fmt.Errorf("failed to insert repository %s: %w", repo.FullName, err)
+		}
+
+		for _, branch := range repo.Branches {
+			if _, err := tx.Exec(`INSERT INTO branches (run_id, repository, name, sha) VALUES (?, ?, ?, ?)`,
+				runID, repo.FullName, branch.Name, branch.SHA); err != nil {
+				return fmt.Errorf("failed to insert branch %s@%s: %w", repo.FullName, branch.Name, err)
+			}
+
+			for _, commit := range branch.Commits {
+				if _, err := tx.Exec(`INSERT OR IGNORE INTO commit_branches (run_id, repository, sha, branch) VALUES (?, ?, ?, ?)`,
+					runID, repo.FullName, commit.SHA, branch.Name); err != nil {
+					return fmt.Errorf("failed to insert commit branch %s: %w", commit.SHA, err)
+				}
+			}
+		}
+
+		for _, commit := range uniqueCommits(repo) {
+			authorKey := addAuthor(commit.Author)
+			var committerKey any
+			if commit.Committer != (models.Author{}) {
+				committerKey = addAuthor(commit.Committer)
+			}
+			var pullRequest any
+			if commit.PullRequest != nil {
+				pullRequest = commit.PullRequest.Number
+				addAuthor(commit.PullRequest.Author)
+			}
+
+			if _, err := tx.Exec(`INSERT INTO commits (run_id, repository, sha, author_key, committer_key, authored_at, committed_at, message, additions, deletions, total, pull_request)
+				VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+				runID, repo.FullName, commit.SHA, authorKey, committerKey, sqliteTime(commit.Date), sqliteTime(commit.CommitDate),
+				commit.Message, commit.Stats.Additions, commit.Stats.Deletions, commit.Stats.Total, pullRequest); err != nil {
+				return fmt.Errorf("failed to insert commit %s: %w", commit.SHA, err)
+			}
+		}
+	}
+
+	for key, stats := range report.Summary {
+		if _, exists := authors[key]; !exists {
+			authors[key] = models.Author{Name: stats.Name, Email: stats.Email, Login: stats.Login}
+		}
+		for repoName, repoStats := range stats.Repositories {
+			if _, err := tx.Exec(`INSERT INTO contributor_stats (run_id, author_key, repository, commits, additions, deletions) VALUES (?, ?, ?, ?, ?, ?)`,
+				runID, key, repoName, repoStats.Commits, repoStats.Additions, repoStats.Deletions); err != nil {
+				return fmt.Errorf("failed to insert contributor stats for %s: %w", key, err)
+			}
+		}
+	}
+
+	// Team totals are the sums of team_stats; a commit belongs to a single repository
+	for name, team := range report.Teams {
+		for _, member := range team.Members {
+			if _, err := tx.Exec(`INSERT INTO team_members (run_id, team, member) VALUES (?, ?, ?)`,
+				runID, name, member); err != nil {
+				return fmt.Errorf("failed to insert member of team %s: %w", name, err)
+			}
+		}
+		for repoName, repoStats := range team.Repositories {
+			if _, err := tx.Exec(`INSERT INTO team_stats (run_id, team, repository, commits, additions, deletions) VALUES (?, ?, ?, ?, ?, ?)`,
+				runID, name, repoName, repoStats.Commits, repoStats.Additions, repoStats.Deletions); err != nil {
+				return fmt.Errorf("failed to insert team stats for %s: %w", name, err)
+			}
+		}
+	}
+
+	for key, author := range authors {
+		if _, err := tx.Exec(`INSERT INTO authors (run_id, author_key, name, email, login) VALUES (?, ?, ?, ?, ?)`,
+			runID, key, author.Name, author.Email, author.Login); err != nil {
+			return fmt.Errorf("failed to insert author %s: %w", key, err)
+		}
+	}
+
+	return nil
+}
+
+// sqliteTime formats a time as RFC 3339 text, the representation SQLite date functions understand
+func sqliteTime(t time.Time) any {
+	if t.IsZero() {
+		return nil
+	}
+	return t.UTC().Format(time.RFC3339)
+}
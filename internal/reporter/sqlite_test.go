@@ -0,0 +1,60 @@
+package reporter
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+)
+
+func TestOutputSQLite(t *testing.T) {
+	dbFile := filepath.Join(t.TempDir(), "report.db")
+	report := testReport()
+	r := &Reporter{}
+
+	for _, runID := range []string{"run-1", "run-2"} {
+		r.SetRunID(runID)
+		if err := r.OutputReport(report, dbFile, "sqlite"); err != nil {
+			t.Fatalf("Failed to write run %s: %v", runID, err)
+		}
+	}
+
+	r.SetRunID("run-1")
+	if err := r.OutputReport(report, dbFile, "sqlite"); err == nil {
+		t.Error("Expected error when appending a duplicate run ID")
+	}
+
+	db, err := sql.Open("sqlite", dbFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	counts := map[string]int{
+		"runs":              2,
+		"repositories":      2,
+		"branches":          2,
+		"commits":           4,
+		"commit_branches":   4,
+		"authors":           4,
+		"contributor_stats": 4,
+	}
+	for table, expected := range counts {
+		var count int
+		if err := db.QueryRow("SELECT COUNT(*) FROM " + table).Scan(&count); err != nil {
+			t.Fatalf("Failed to count %s: %v", table, err)
+		}
+		if count != expected {
+			t.Errorf("Expected %d rows in %s, got %d", expected, table, count)
+		}
+	}
+
+	var additions int
+	err = db.QueryRow(`SELECT SUM(c.additions) FROM commits c JOIN authors a ON a.run_id = c.run_id AND a.author_key = c.author_key
+		WHERE c.run_id = 'run-2' AND a.login = 'johndoe'`).Scan(&additions)
+	if err != nil {
+		t.Fatalf("Failed to query commits: %v", err)
+	}
+	if additions != 10 {
+		t.Errorf("Expected 10 additions for johndoe, got %d", additions)
+	}
+}
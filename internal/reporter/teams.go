@@ -0,0 +1,298 @@
+package reporter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+
+	"ghreporting/internal/models"
+)
+
+// AddTeamMembers adds team memberships, keyed by team name, used to aggregate the
+// summary per team. Members are logins, emails or contributor keys and may belong to
+// several teams. Memberships from several sources are combined.
+func (r *Reporter) AddTeamMembers(teams map[string][]string) {
+	if r.teams == nil {
+		r.teams = make(map[string][]string)
+	}
+	for team, members := range teams {
+		r.teams[team] = appendUnique(r.teams[team], members...)
+	}
+}
+
+// SetGitHubTeams configures whether the team memberships of the organization a report
+// is generated for are fetched from GitHub and added to the configured memberships
+func (r *Reporter) SetGitHubTeams(enabled bool) {
+	r.githubTeams = enabled
+}
+
+// LoadTeamMapping reads a JSON file mapping each person (login, email or contributor
+// key) to a team name or a list of team names, and returns the members of each team
+func LoadTeamMapping(path string) (map[string][]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read team mapping: %w", err)
+	}
+
+	var mapping map[string]json.RawMessage
+	if err := json.Unmarshal(data, &mapping); err != nil {
+		return nil, fmt.Errorf("failed to parse team mapping: %w", err)
+	}
+
+	teams := make(map[string][]string)
+	for person, value := range mapping {
+		var names []string
+		var name string
+		if err := json.Unmarshal(value, &name); err == nil {
+			names = []string{name}
+		} else if err := json.Unmarshal(value, &names); err != nil {
+			return nil, fmt.Errorf("invalid team mapping for %s: expected a team name or a list of team names", person)
+		}
+		for _, name := range names {
+			if name == "" {
+				return nil, fmt.Errorf("invalid team mapping for %s: empty team name", person)
+			}
+			teams[name] = appendUnique(teams[name], person)
+		}
+	}
+	return teams, nil
+}
+
+// teamMembers returns the team memberships used to regroup existing reports: the
+// configured memberships, or the memberships recorded in the reports when none are
+// configured or they include GitHub teams, which are only fetched for new reports
+func (r *Reporter) teamMembers(reports ...*models.Report) map[string][]string {
+	if len(r.teams) > 0 && !r.githubTeams {
+		return r.teams
+	}
+	teams := make(map[string][]string)
+	for _, report := range reports {
+		for name, team := range report.Teams {
+			teams[name] = appendUnique(teams[name], team.Members...)
+		}
+	}
+	if len(teams) == 0 {
+		return r.teams
+	}
+	return teams
+}
+
+// reportTeams returns the team memberships used for a report on target, including
+// its GitHub teams when enabled. When the teams cannot be fetched, e.g. because the
+// target is a user rather than an organization, a warning is logged and the report is
+// aggregated over the configured teams only; no report data is missing. Teams whose
+// members cannot be fetched are left out and recorded in the diagnostics.
+func (r *Reporter) reportTeams(ctx context.Context, target string, diagnostics *diagnosticLog) map[string][]string {
+	if !r.githubTeams || ctx.Err() != nil {
+		return r.teams
+	}
+
+	fetched, failures, err := r.client.ListTeamMembers(ctx, target)
+	if err != nil {
+		slog.Warn("Failed to fetch GitHub teams", "target", target, "error", err)
+		return r.teams
+	}
+	for _, failure := range failures {
+		slog.Warn("Failed to fetch GitHub team members", "target", target, "team", failure.Team, "error", failure.Err)
+		diagnostics.addTeam(failure.Team, failure.Err)
+	}
+	slog.Info("Fetched GitHub teams", "target", target, "teams", len(fetched))
+
+	teams := make(map[string][]string, len(r.teams)+len(fetched))
+	for _, source := range []map[string][]string{r.teams, fetched} {
+		for name, members := range source {
+			teams[name] = appendUnique(teams[name], members...)
+		}
+	}
+	return teams
+}
+
+// generateTeams aggregates the commits of the repositories per team. A commit counts
+// once for a team however many of its members are credited; the team's line changes
+// are those credited to its members, capped at the commit's own changes. Members
+// matched to the same contributor, e.g. by login and by email, count as one person.
+// Returns nil when no team is configured.
+func (r *Reporter) generateTeams(repos []models.Repository, members map[string][]string) map[string]models.TeamStats {
+	if len(members) == 0 {
+		return nil
+	}
+
+	// Index members case-insensitively; a person may be listed by login or email
+	memberTeams := make(map[string][]string)
+	teams := make(map[string]models.TeamStats, len(members))
+	for name, teamMembers := range members {
+		sorted := append([]string(nil), teamMembers...)
+		sort.Strings(sorted)
+		teams[name] = models.TeamStats{
+			Name:         name,
+			Members:      sorted,
+			Contributors: []string{},
+			Repositories: make(map[string]models.RepositoryStats),
+		}
+		for _, member := range teamMembers {
+			key := strings.ToLower(member)
+			memberTeams[key] = appendUnique(memberTeams[key], name)
+		}
+	}
+
+	// Contributor keys of the members matched by commits, per team
+	matched := make(map[string]map[string]string, len(members))
+	for _, repo := range repos {
+		for _, branch := range repo.Branches {
+			for _, commit := range branch.Commits {
+				r.addTeamContribution(teams, memberTeams, matched, repo.FullName, commit)
+			}
+		}
+	}
+
+	for name, team := range teams {
+		sort.Strings(team.Contributors)
+		team.MemberCount = countMembers(team.Members, matched[name])
+		teams[name] = team
+	}
+	return teams
+}
+
+// countMembers returns the number of distinct people among the members of a team.
+// Members matched to the same contributor count once, as do members that differ only
+// in case.
+func countMembers(members []string, matched map[string]string) int {
+	contributors := make(map[string]bool)
+	unmatched := make(map[string]bool)
+	for _, member := range members {
+		id := strings.ToLower(member)
+		if key, ok := matched[id]; ok {
+			contributors[key] = true
+		} else {
+			unmatched[id] = true
+		}
+	}
+	return len(contributors) + len(unmatched)
+}
+
+// addTeamContribution credits a commit to every team one of its contributors belongs
+// to, and records the contributor key each matching member refers to
+func (r *Reporter) addTeamContribution(teams map[string]models.TeamStats, memberTeams map[string][]string, matched map[string]map[string]string, repoName string, commit models.Commit) {
+	type share struct{ additions, deletions int }
+	shares := make(map[string]share)
+	var credited []string
+	for _, credit := range r.commitCredits(commit) {
+		key := r.getAuthorKey(credit.author)
+
+		var names []string
+		for _, id := range []string{key, credit.author.Login, credit.author.Email} {
+			if id == "" {
+				continue
+			}
+			id = strings.ToLower(id)
+			for _, name := range memberTeams[id] {
+				names = appendUnique(names, name)
+				if matched[name] == nil {
+					matched[name] = make(map[string]string)
+				}
+				matched[name][id] = key
+			}
+		}
+		for _, name := range names {
+			s, exists := shares[name]
+			if !exists {
+				credited = append(credited, name)
+			}
+			shares[name] = share{s.additions + credit.additions, s.deletions + credit.deletions}
+
+			team := teams[name]
+			team.Contributors = appendUnique(team.Contributors, key)
+			teams[name] = team
+		}
+	}
+
+	for _, name := range credited {
+		additions := min(shares[name].additions, commit.Stats.Additions)
+		deletions := min(shares[name].deletions, commit.Stats.Deletions)
+
+		team := teams[name]
+		team.TotalCommits++
+		team.TotalAdditions += additions
+		team.TotalDeletions += deletions
+
+		repoStats := team.Repositories[repoName]
+		repoStats.Commits++
+		repoStats.Additions += additions
+		repoStats.Deletions += deletions
+		team.Repositories[repoName] = repoStats
+
+		teams[name] = team
+	}
+}
+
+// sortedTeams returns the team names in the configured contributor order
+func (r *Reporter) sortedTeams(report *models.Report) []string {
+	less, ok := contributorOrders[r.sortOrder]
+	if !ok {
+		less = contributorOrders["changes"]
+	}
+
+	stats := func(name string) models.ContributorStats {
+		team := report.Teams[name]
+		return models.ContributorStats{
+			Name:           team.Name,
+			TotalCommits:   team.TotalCommits,
+			TotalAdditions: team.TotalAdditions,
+			TotalDeletions: team.TotalDeletions,
+		}
+	}
+
+	var names []string
+	for name := range report.Teams {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		a, b := stats(names[i]), stats(names[j])
+		if less(a, b) != less(b, a) {
+			return less(a, b)
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
+// teamRepositories returns the repository names of a team ordered by changes
+func teamRepositories(team models.TeamStats) []string {
+	return sortedRepositories(models.ContributorStats{Repositories: team.Repositories})
+}
+
+// contributorTeams returns the sorted names of the teams a contributor belongs to
+func contributorTeams(report *models.Report, contributor string) []string {
+	var names []string
+	for name, team := range report.Teams {
+		for _, key := range team.Contributors {
+			if key == contributor {
+				names = append(names, name)
+				break
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// appendUnique appends the values not already in list
+func appendUnique(list []string, values ...string) []string {
+	for _, value := range values {
+		found := false
+		for _, existing := range list {
+			if existing == value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			list = append(list, value)
+		}
+	}
+	return list
+}
@@ -0,0 +1,240 @@
+package reporter
+
+import (
+	"bytes"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/xuri/excelize/v2"
+
+	"ghreporting/internal/models"
+)
+
+// testTeamReport returns the test report aggregated over two teams; johndoe belongs to both
+func testTeamReport(r *Reporter) *models.Report {
+	r.AddTeamMembers(map[string][]string{
+		"Platform": {"johndoe", "Jane@Example.com"},
+		"Web":      {"johndoe", "nobody"},
+	})
+	report := testReport()
+	report.Teams = r.generateTeams(report.Repositories, r.teams)
+	return report
+}
+
+func TestGenerateTeams(t *testing.T) {
+	r := &Reporter{}
+	report := testTeamReport(r)
+
+	platform := report.Teams["Platform"]
+	if platform.TotalCommits != 2 || platform.TotalAdditions != 13 || platform.TotalDeletions != 6 {
+		t.Errorf("Unexpected Platform totals: %+v", platform)
+	}
+	if repo := platform.Repositories["owner/repo1"]; repo.Commits != 2 || repo.Additions != 13 {
+		t.Errorf("Unexpected Platform repository stats: %+v", repo)
+	}
+	if strings.Join(platform.Contributors, ",") != "janesmith,johndoe" {
+		t.Errorf("Expected members matched by login and email, got %v", platform.Contributors)
+	}
+
+	web := report.Teams["Web"]
+	if web.TotalCommits != 1 || web.TotalAdditions != 10 || len(web.Members) != 2 || len(web.Contributors) != 1 {
+		t.Errorf("Unexpected Web totals: %+v", web)
+	}
+
+	// A person listed by login and by email counts once
+	teams := r.generateTeams(report.Repositories, map[string][]string{
+		"Platform": {"johndoe", "John@Example.com", "jane@example.com", "nobody"},
+	})
+	if platform := teams["Platform"]; len(platform.Members) != 4 || platform.MemberCount != 3 || len(platform.Contributors) != 2 {
+		t.Errorf("Expected 4 members naming 3 people, 2 of them active, got %+v", platform)
+	}
+
+	if teams := r.generateTeams(report.Repositories, nil); teams != nil {
+		t.Errorf("Expected no teams without memberships, got %v", teams)
+	}
+}
+
+func TestGenerateTeamsCountsSharedCommitsOnce(t *testing.T) {
+	repos := []models.Repository{{
+		FullName: "owner/repo1",
+		Branches: []models.Branch{{
+			Name: "main",
+			Commits: []models.Commit{{
+				SHA:     "abc123",
+				Message: "Pair on feature\n\nCo-authored-by: Jane Smith <jane@example.com>",
+				Author:  models.Author{Name: "John Doe", Email: "john@example.com", Login: "johndoe"},
+				Stats:   models.CommitStats{Additions: 10, Deletions: 4, Total: 14},
+			}},
+		}},
+	}}
+	members := map[string][]string{"Platform": {"johndoe", "jane@example.com"}}
+
+	for _, split := range []CoAuthorSplit{CoAuthorSplitAuthor, CoAuthorSplitEven, CoAuthorSplitFull} {
+		r := &Reporter{coAuthors: true, coAuthorSplit: split}
+		team := r.generateTeams(repos, members)["Platform"]
+		if team.TotalCommits != 1 || team.TotalAdditions != 10 || team.TotalDeletions != 4 {
+			t.Errorf("%s split: expected 1 commit +10/-4, got %d commits +%d/-%d",
+				split, team.TotalCommits, team.TotalAdditions, team.TotalDeletions)
+		}
+		if len(team.Contributors) != 2 {
+			t.Errorf("%s split: expected both members active, got %v", split, team.Contributors)
+		}
+	}
+}
+
+func TestLoadTeamMapping(t *testing.T) {
+	write := func(content string) string {
+		path := filepath.Join(t.TempDir(), "teams.json")
+		os.WriteFile(path, []byte(content), 0644)
+		return path
+	}
+
+	teams, err := LoadTeamMapping(write(`{"johndoe": "Platform", "jane@example.com": ["Platform", "Web"]}`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(teams["Platform"]) != 2 || len(teams["Web"]) != 1 || teams["Web"][0] != "jane@example.com" {
+		t.Errorf("Unexpected teams: %v", teams)
+	}
+
+	for name, content := range map[string]string{
+		"not an object": `["Platform"]`,
+		"bad value":     `{"johndoe": 1}`,
+		"empty team":    `{"johndoe": ""}`,
+	} {
+		if _, err := LoadTeamMapping(write(content)); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestFilterReportKeepsSavedTeams(t *testing.T) {
+	saved := testTeamReport(&Reporter{})
+
+	filtered, err := (&Reporter{}).FilterReport(saved, ReportFilter{
+		Since: time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	platform := filtered.Teams["Platform"]
+	if platform.TotalCommits != 1 || platform.TotalAdditions != 3 || len(platform.Members) != 2 {
+		t.Errorf("Expected saved memberships to be re-aggregated, got %+v", platform)
+	}
+	if web := filtered.Teams["Web"]; web.TotalCommits != 0 || len(web.Repositories) != 0 {
+		t.Errorf("Expected Web to have no commits in the filtered period, got %+v", web)
+	}
+}
+
+func TestTeamOutputs(t *testing.T) {
+	r := &Reporter{}
+	report := testTeamReport(r)
+
+	tests := map[string][]string{
+		"text":        {"TEAM SUMMARY", "Platform\n  Active Members: 2 of 2\n  Total Commits: 2", "    - owner/repo1: 1 commits (+10/-5)"},
+		"csv":         {"Deletions,Teams\n", "John Doe,johndoe,john@example.com,owner/repo1,1,10,5,Platform;Web"},
+		"markdown":    {"## Team Summary", "| Platform | 2 / 2 | 2 | +13 | -6 | 1 |", "<summary><strong>Web</strong> — 1 commit, +10/-5</summary>"},
+		"html":        {"<h2>Teams</h2>", "<td>Platform</td><td class=\"num\" data-sort=\"2\">2 / 2</td>"},
+		"openmetrics": {`ghreporting_team_commits{target="owner",team="Platform",repo="owner/repo1"} 2`, `ghreporting_team_members{target="owner",team="Web"} 2`},
+		"slack":       {"*Teams*", "1. *Platform* — 2 commits, +13 / -6, mostly in owner/repo1"},
+	}
+	for format, wants := range tests {
+		var buf bytes.Buffer
+		if err := r.WriteReport(&buf, report, format); err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+		for _, want := range wants {
+			if !strings.Contains(buf.String(), want) {
+				t.Errorf("%s: expected output to contain %q, got:\n%s", format, want, buf.String())
+			}
+		}
+	}
+
+	var plain bytes.Buffer
+	if err := r.WriteReport(&plain, testReport(), "csv"); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(plain.String(), "Teams") {
+		t.Errorf("Expected no Teams column without teams, got:\n%s", plain.String())
+	}
+}
+
+func TestTeamTables(t *testing.T) {
+	r := &Reporter{}
+	report := testTeamReport(r)
+	dir := t.TempDir()
+
+	dbFile := filepath.Join(dir, "report.db")
+	r.SetRunID("run-1")
+	if err := r.OutputReport(report, dbFile, "sqlite"); err != nil {
+		t.Fatalf("Failed to write SQLite: %v", err)
+	}
+	db, err := sql.Open("sqlite", dbFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	var members, commits int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM team_members WHERE team = 'Web'`).Scan(&members); err != nil {
+		t.Fatalf("Failed to query team_members: %v", err)
+	}
+	if err := db.QueryRow(`SELECT SUM(commits) FROM team_stats WHERE team = 'Platform'`).Scan(&commits); err != nil {
+		t.Fatalf("Failed to query team_stats: %v", err)
+	}
+	if members != 2 || commits != 2 {
+		t.Errorf("Expected 2 Web members and 2 Platform commits, got %d and %d", members, commits)
+	}
+
+	if err := r.OutputReport(report, dir, "parquet"); err != nil {
+		t.Fatalf("Failed to write Parquet: %v", err)
+	}
+	rows, err := parquet.ReadFile[parquetTeam](filepath.Join(dir, "teams", "period_start=2024-01-01", "period_end=2024-01-31", "owner.parquet"))
+	if err != nil {
+		t.Fatalf("Failed to read teams table: %v", err)
+	}
+	if len(rows) != 2 || rows[0].Team != "Platform" || rows[0].Commits != 2 {
+		t.Errorf("Unexpected team rows: %+v", rows)
+	}
+
+	xlsxFile := filepath.Join(dir, "report.xlsx")
+	if err := r.OutputReport(report, xlsxFile, "xlsx"); err != nil {
+		t.Fatalf("Failed to write workbook: %v", err)
+	}
+	f, err := excelize.OpenFile(xlsxFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	teamRows, err := f.GetRows(xlsxTeamReposSheet)
+	if err != nil {
+		t.Fatalf("Missing %s sheet: %v", xlsxTeamReposSheet, err)
+	}
+	if len(teamRows) != 3 || teamRows[1][0] != "Platform" || teamRows[1][1] != "owner/repo1" {
+		t.Errorf("Unexpected team repository rows: %v", teamRows)
+	}
+}
+
+func TestTeamMembers(t *testing.T) {
+	saved := testTeamReport(&Reporter{})
+
+	if teams := (&Reporter{}).teamMembers(saved); len(teams) != 2 || len(teams["Web"]) != 2 {
+		t.Errorf("Expected the saved memberships, got %v", teams)
+	}
+
+	configured := &Reporter{}
+	configured.AddTeamMembers(map[string][]string{"Ops": {"janesmith"}})
+	if teams := configured.teamMembers(saved); len(teams) != 1 || teams["Ops"] == nil {
+		t.Errorf("Expected configured memberships to regroup the report, got %v", teams)
+	}
+
+	// GitHub teams are only fetched for new reports, so recorded teams are kept
+	configured.SetGitHubTeams(true)
+	if teams := configured.teamMembers(saved); len(teams) != 2 || teams["Ops"] != nil {
+		t.Errorf("Expected the recorded memberships with GitHub teams, got %v", teams)
+	}
+}
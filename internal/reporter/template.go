@@ -0,0 +1,192 @@
+package reporter
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"ghreporting/internal/models"
+)
+
+// SetTemplate configures the template file used by the "template" output format.
+// Files named *.html, *.htm, *.gohtml or *.html.tmpl are executed with html/template
+// (contextual escaping), everything else with text/template.
+func (r *Reporter) SetTemplate(path string) {
+	r.templatePath = path
+}
+
+// RepositoryEntry pairs a repository name with a contributor's stats in it, for templates
+type RepositoryEntry struct {
+	Name string
+	models.RepositoryStats
+}
+
+// reportTemplate is a parsed text/template or html/template
+type reportTemplate interface {
+	Execute(w io.Writer, data any) error
+}
+
+func (r *Reporter) outputTemplate(report *models.Report, outputFile string) error {
+	tmpl, err := r.parseTemplate()
+	if err != nil {
+		return err
+	}
+
+	// Render before opening the output so a failing template leaves it untouched
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, report); err != nil {
+		return fmt.Errorf("failed to execute template: %w", err)
+	}
+
+	output, err := openOutput(outputFile)
+	if err != nil {
+		return err
+	}
+	defer output.Close()
+
+	if _, err := buf.WriteTo(output); err != nil {
+		return fmt.Errorf("failed to write template output: %w", err)
+	}
+	return nil
+}
+
+// parseTemplate reads and parses the configured template file
+func (r *Reporter) parseTemplate() (reportTemplate, error) {
+	if r.templatePath == "" {
+		return nil, fmt.Errorf("template output format requires a template file")
+	}
+
+	source, err := os.ReadFile(r.templatePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template: %w", err)
+	}
+
+	name := filepath.Base(r.templatePath)
+	var tmpl reportTemplate
+	if isHTMLTemplate(name) {
+		tmpl, err = htmltemplate.New(name).Funcs(htmltemplate.FuncMap(r.templateFuncs())).Parse(string(source))
+	} else {
+		tmpl, err = template.New(name).Funcs(r.templateFuncs()).Parse(string(source))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template: %w", err)
+	}
+	return tmpl, nil
+}
+
+func isHTMLTemplate(name string) bool {
+	name = strings.ToLower(strings.TrimSuffix(name, ".tmpl"))
+	switch filepath.Ext(name) {
+	case ".html", ".htm", ".gohtml":
+		return true
+	}
+	return false
+}
+
+// templateFuncs returns the helper functions available to user templates
+func (r *Reporter) templateFuncs() template.FuncMap {
+	return template.FuncMap{
+		// contributors returns the report summary in the configured order
+		"contributors": func(report *models.Report) []models.ContributorStats {
+			var result []models.ContributorStats
+			for _, key := range r.sortedContributors(report) {
+				result = append(result, report.Summary[key])
+			}
+			return result
+		},
+		// sortContributors orders contributors by commits, additions, deletions, changes or name
+		"sortContributors": sortContributorStats,
+		// repositories returns a contributor's repositories sorted by changes
+		"repositories": func(stats models.ContributorStats) []RepositoryEntry {
+			var result []RepositoryEntry
+			for _, name := range sortedRepositories(stats) {
+				result = append(result, RepositoryEntry{Name: name, RepositoryStats: stats.Repositories[name]})
+			}
+			return result
+		},
+		// teams returns the team summary in the configured order
+		"teams": func(report *models.Report) []models.TeamStats {
+			var result []models.TeamStats
+			for _, name := range r.sortedTeams(report) {
+				result = append(result, report.Teams[name])
+			}
+			return result
+		},
+		// teamRepositories returns a team's repositories sorted by changes
+		"teamRepositories": func(team models.TeamStats) []RepositoryEntry {
+			var result []RepositoryEntry
+			for _, name := range teamRepositories(team) {
+				result = append(result, RepositoryEntry{Name: name, RepositoryStats: team.Repositories[name]})
+			}
+			return result
+		},
+		"repoTotals": repositoryTotals,
+		"commits":    uniqueCommits,
+		"top":        top,
+		"number":     formatNumber,
+		"date":       func(t time.Time) string { return t.Format("2006-01-02") },
+		"formatDate": func(layout string, t time.Time) string { return t.Format(layout) },
+		"subject": func(message string) string {
+			subject, _, _ := strings.Cut(message, "\n")
+			return subject
+		},
+		"add":    func(a, b int) int { return a + b },
+		"sub":    func(a, b int) int { return a - b },
+		"plural": plural,
+		"upper":  strings.ToUpper,
+		"lower":  strings.ToLower,
+		"join":   strings.Join,
+		"repeat": strings.Repeat,
+	}
+}
+
+// sortContributorStats returns a copy of contributors sorted by the given field
+func sortContributorStats(field string, contributors []models.ContributorStats) ([]models.ContributorStats, error) {
+	if err := ValidateSortOrder(field); err != nil {
+		return nil, err
+	}
+	less := contributorOrders[field]
+
+	sorted := append([]models.ContributorStats(nil), contributors...)
+	sort.SliceStable(sorted, func(i, j int) bool { return less(sorted[i], sorted[j]) })
+	return sorted, nil
+}
+
+// top returns the first n elements of a slice
+func top(n int, list any) (any, error) {
+	value := reflect.ValueOf(list)
+	if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+		return nil, fmt.Errorf("top: expected a slice, got %T", list)
+	}
+	if n < value.Len() {
+		return value.Slice(0, n).Interface(), nil
+	}
+	return list, nil
+}
+
+// formatNumber formats an integer with thousands separators, e.g. 1250 as "1,250"
+func formatNumber(n int) string {
+	digits := strconv.Itoa(n)
+	sign := ""
+	if n < 0 {
+		sign, digits = "-", digits[1:]
+	}
+
+	var b strings.Builder
+	for i, digit := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(digit)
+	}
+	return sign + b.String()
+}
@@ -0,0 +1,98 @@
+package reporter
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestOutputTemplate(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		name     string
+		file     string
+		source   string
+		expected string
+	}{
+		{
+			name:     "text template",
+			file:     "report.tmpl",
+			source:   `{{.Target}}:{{range top 1 (contributors .)}} {{.Login}}={{number .TotalAdditions}}{{end}}`,
+			expected: "owner: johndoe=10",
+		},
+		{
+			name:     "sorted by name",
+			file:     "names.txt",
+			source:   `{{range sortContributors "name" (contributors .)}}{{.Name}};{{end}}`,
+			expected: "Jane Smith;John Doe;",
+		},
+		{
+			name:     "html template escapes",
+			file:     "report.html.tmpl",
+			source:   `{{range (index .Repositories 0).Branches}}{{range .Commits}}<li>{{subject .Message}}</li>{{end}}{{end}}`,
+			expected: "<li>Add feature</li><li>Fix &lt;script&gt; escaping</li>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			templateFile := filepath.Join(dir, tt.file)
+			if err := os.WriteFile(templateFile, []byte(tt.source), 0644); err != nil {
+				t.Fatal(err)
+			}
+			outputFile := filepath.Join(dir, "out")
+
+			r := &Reporter{}
+			r.SetTemplate(templateFile)
+			if err := r.OutputReport(testReport(), outputFile, "template"); err != nil {
+				t.Fatalf("Failed to render template: %v", err)
+			}
+
+			data, err := os.ReadFile(outputFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.TrimSpace(string(data)) != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, string(data))
+			}
+		})
+	}
+}
+
+func TestOutputTemplateErrors(t *testing.T) {
+	dir := t.TempDir()
+	outputFile := filepath.Join(dir, "out")
+	if err := os.WriteFile(outputFile, []byte("previous"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	invalid := filepath.Join(dir, "invalid.tmpl")
+	os.WriteFile(invalid, []byte(`{{range .Repositories}}`), 0644)
+	r := &Reporter{}
+	r.SetTemplate(invalid)
+	if err := r.ValidateOutputTargets([]OutputTarget{{Format: "template", File: outputFile}}); err == nil {
+		t.Error("Expected a template that does not parse to fail validation")
+	}
+
+	// A template failing while executing leaves the existing output untouched
+	failing := filepath.Join(dir, "failing.tmpl")
+	os.WriteFile(failing, []byte(`partial {{top 1 .Target}}`), 0644)
+	r.SetTemplate(failing)
+	if err := r.OutputReport(testReport(), outputFile, "template"); err == nil {
+		t.Error("Expected an error executing the template")
+	}
+	if data, _ := os.ReadFile(outputFile); string(data) != "previous" {
+		t.Errorf("Expected the output to be untouched, got %q", data)
+	}
+}
+
+func TestFormatNumber(t *testing.T) {
+	tests := map[int]string{0: "0", 999: "999", 1250: "1,250", 1234567: "1,234,567", -4500: "-4,500"}
+	for n, expected := range tests {
+		if result := formatNumber(n); result != expected {
+			t.Errorf("formatNumber(%d): expected %s, got %s", n, expected, result)
+		}
+	}
+}
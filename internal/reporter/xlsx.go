@@ -0,0 +1,353 @@
+package reporter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+
+	"ghreporting/internal/models"
+)
+
+const (
+	xlsxSummarySheet   = "Summary"
+	xlsxMatrixSheet    = "Matrix"
+	xlsxCommitsSheet   = "Commits"
+	xlsxMetadataSheet  = "Metadata"
+	xlsxTeamsSheet     = "Teams"
+	xlsxTeamReposSheet = "Team Repositories"
+)
+
+// xlsxCommitLimit is the number of commits the Commits sheet holds: a worksheet has at
+// most excelize.TotalRows rows, one of which is the header
+var xlsxCommitLimit = excelize.TotalRows - 1
+
+// xlsxWorkbook wraps an excelize file with the styles shared by all sheets
+type xlsxWorkbook struct {
+	file        *excelize.File
+	headerStyle int
+	dateStyle   int
+}
+
+func (r *Reporter) outputXLSX(report *models.Report, outputFile string) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	var err error
+	wb := &xlsxWorkbook{file: f}
+	if wb.headerStyle, err = f.NewStyle(&excelize.Style{
+		Font: &excelize.Font{Bold: true},
+		Fill: excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"DDEBF7"}},
+	}); err != nil {
+		return err
+	}
+	dateFormat := "yyyy-mm-dd hh:mm"
+	if wb.dateStyle, err = f.NewStyle(&excelize.Style{CustomNumFmt: &dateFormat}); err != nil {
+		return err
+	}
+
+	// The default sheet becomes the summary so it opens first
+	if err := f.SetSheetName("Sheet1", xlsxSummarySheet); err != nil {
+		return err
+	}
+	sheets := []string{xlsxMatrixSheet, xlsxCommitsSheet, xlsxMetadataSheet}
+	writers := []func(*xlsxWorkbook, *models.Report) error{
+		r.writeXLSXSummary,
+		r.writeXLSXMatrix,
+		writeXLSXCommits,
+		r.writeXLSXMetadata,
+	}
+	if len(report.Teams) > 0 {
+		sheets = append(sheets, xlsxTeamsSheet, xlsxTeamReposSheet)
+		writers = append(writers, r.writeXLSXTeams)
+	}
+	for _, sheet := range sheets {
+		if _, err := f.NewSheet(sheet); err != nil {
+			return err
+		}
+	}
+
+	for _, write := range writers {
+		if err := write(wb, report); err != nil {
+			return fmt.Errorf("failed to write workbook: %w", err)
+		}
+	}
+
+	// The output is only opened once the workbook is built, so a failure leaves it untouched
+	output, err := openOutput(outputFile)
+	if err != nil {
+		return err
+	}
+	defer output.Close()
+
+	return f.Write(output)
+}
+
+// writeRow writes values starting at column A of the given row; time values get the date style
+func (wb *xlsxWorkbook) writeRow(sheet string, row int, values ...any) error {
+	for i, value := range values {
+		cell, err := excelize.CoordinatesToCellName(i+1, row)
+		if err != nil {
+			return err
+		}
+		if err := wb.file.SetCellValue(sheet, cell, value); err != nil {
+			return err
+		}
+		if _, isTime := value.(time.Time); isTime {
+			if err := wb.file.SetCellStyle(sheet, cell, cell, wb.dateStyle); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// writeHeader writes a bold header row, freezes it and enables filtering on the table
+func (wb *xlsxWorkbook) writeHeader(sheet string, rows int, columns ...string) error {
+	values := make([]any, len(columns))
+	for i, column := range columns {
+		values[i] = column
+	}
+	if err := wb.writeRow(sheet, 1, values...); err != nil {
+		return err
+	}
+
+	lastCell, err := excelize.CoordinatesToCellName(len(columns), 1)
+	if err != nil {
+		return err
+	}
+	if err := wb.file.SetCellStyle(sheet, "A1", lastCell, wb.headerStyle); err != nil {
+		return err
+	}
+	if err := wb.file.SetPanes(sheet, &excelize.Panes{Freeze: true, YSplit: 1, TopLeftCell: "A2", ActivePane: "bottomLeft"}); err != nil {
+		return err
+	}
+
+	lastColumn, err := excelize.ColumnNumberToName(len(columns))
+	if err != nil {
+		return err
+	}
+	return wb.file.AutoFilter(sheet, "A1:"+lastColumn+strconv.Itoa(rows+1), nil)
+}
+
+func (r *Reporter) writeXLSXSummary(wb *xlsxWorkbook, report *models.Report) error {
+	contributors := r.sortedContributors(report)
+	if err := wb.writeHeader(xlsxSummarySheet, len(contributors), "Contributor", "Login", "Email", "Commits", "Additions", "Deletions", "Repositories"); err != nil {
+		return err
+	}
+
+	for i, contributor := range contributors {
+		stats := report.Summary[contributor]
+		if err := wb.writeRow(xlsxSummarySheet, i+2, stats.Name, stats.Login, stats.Email,
+			stats.TotalCommits, stats.TotalAdditions, stats.TotalDeletions, len(stats.Repositories)); err != nil {
+			return err
+		}
+	}
+
+	return wb.file.SetColWidth(xlsxSummarySheet, "A", "C", 28)
+}
+
+// writeXLSXTeams writes the team totals and each team's commits per repository
+func (r *Reporter) writeXLSXTeams(wb *xlsxWorkbook, report *models.Report) error {
+	teams := r.sortedTeams(report)
+	if err := wb.writeHeader(xlsxTeamsSheet, len(teams), "Team", "Members", "Active Members", "Commits", "Additions", "Deletions", "Repositories"); err != nil {
+		return err
+	}
+
+	var rows int
+	for i, name := range teams {
+		team := report.Teams[name]
+		if err := wb.writeRow(xlsxTeamsSheet, i+2, team.Name, team.MemberCount, len(team.Contributors),
+			team.TotalCommits, team.TotalAdditions, team.TotalDeletions, len(team.Repositories)); err != nil {
+			return err
+		}
+		for _, repoName := range teamRepositories(team) {
+			repoStats := team.Repositories[repoName]
+			rows++
+			if err := wb.writeRow(xlsxTeamReposSheet, rows+1, team.Name, repoName,
+				repoStats.Commits, repoStats.Additions, repoStats.Deletions); err != nil {
+				return err
+			}
+		}
+	}
+	if err := wb.file.SetColWidth(xlsxTeamsSheet, "A", "A", 28); err != nil {
+		return err
+	}
+
+	if err := wb.writeHeader(xlsxTeamReposSheet, rows, "Team", "Repository", "Commits", "Additions", "Deletions"); err != nil {
+		return err
+	}
+	return wb.file.SetColWidth(xlsxTeamReposSheet, "A", "B", 28)
+}
+
+// writeXLSXMatrix writes commits per contributor (rows) and repository (columns)
+func (r *Reporter) writeXLSXMatrix(wb *xlsxWorkbook, report *models.Report) error {
+	contributors := r.sortedContributors(report)
+
+	var repoNames []string
+	for _, repo := range report.Repositories {
+		repoNames = append(repoNames, repo.FullName)
+	}
+
+	header := append([]string{"Contributor"}, repoNames...)
+	header = append(header, "Total")
+	if err := wb.writeHeader(xlsxMatrixSheet, len(contributors), header...); err != nil {
+		return err
+	}
+
+	for i, contributor := range contributors {
+		stats := report.Summary[contributor]
+		values := []any{contributor}
+		for _, repoName := range repoNames {
+			values = append(values, stats.Repositories[repoName].Commits)
+		}
+		values = append(values, stats.TotalCommits)
+		if err := wb.writeRow(xlsxMatrixSheet, i+2, values...); err != nil {
+			return err
+		}
+	}
+
+	return wb.file.SetColWidth(xlsxMatrixSheet, "A", "A", 28)
+}
+
+// writeXLSXCommits streams one row per commit, so large reports are not held as cells
+// in memory. Commits beyond xlsxCommitLimit are left out and noted in the metadata.
+func writeXLSXCommits(wb *xlsxWorkbook, report *models.Report) error {
+	sw, err := wb.file.NewStreamWriter(xlsxCommitsSheet)
+	if err != nil {
+		return err
+	}
+	for _, width := range []struct {
+		min, max int
+		width    float64
+	}{{1, 7, 20}, {10, 10, 60}} {
+		if err := sw.SetColWidth(width.min, width.max, width.width); err != nil {
+			return err
+		}
+	}
+	if err := sw.SetPanes(&excelize.Panes{Freeze: true, YSplit: 1, TopLeftCell: "A2", ActivePane: "bottomLeft"}); err != nil {
+		return err
+	}
+
+	columns := []string{"Repository", "Branch", "SHA", "Author", "Login", "Email", "Date",
+		"Additions", "Deletions", "Subject", "Pull Request"}
+	header := make([]any, len(columns))
+	for i, column := range columns {
+		header[i] = excelize.Cell{StyleID: wb.headerStyle, Value: column}
+	}
+	if err := sw.SetRow("A1", header); err != nil {
+		return err
+	}
+
+	row := 2
+	for _, repo := range report.Repositories {
+		for _, branch := range repo.Branches {
+			for _, commit := range branch.Commits {
+				if row-1 > xlsxCommitLimit {
+					break
+				}
+				record := NewCommitRecord(repo.FullName, branch.Name, commit)
+				var pullRequest any
+				if commit.PullRequest != nil {
+					pullRequest = commit.PullRequest.Number
+				}
+				cell, err := excelize.CoordinatesToCellName(1, row)
+				if err != nil {
+					return err
+				}
+				if err := sw.SetRow(cell, []any{record.Repository, record.Branch, record.SHA,
+					record.AuthorName, record.AuthorLogin, record.AuthorEmail,
+					excelize.Cell{StyleID: wb.dateStyle, Value: record.Date},
+					record.Additions, record.Deletions, record.Subject, pullRequest}); err != nil {
+					return err
+				}
+				row++
+			}
+		}
+	}
+
+	// A table without a style adds the header filter to a streamed sheet
+	lastCell, err := excelize.CoordinatesToCellName(len(columns), max(row-1, 2))
+	if err != nil {
+		return err
+	}
+	if err := sw.AddTable(&excelize.Table{Range: "A1:" + lastCell, Name: "Commits"}); err != nil {
+		return err
+	}
+	return sw.Flush()
+}
+
+// reportCommitCount returns the number of commit rows in the report, one per branch
+func reportCommitCount(report *models.Report) int {
+	count := 0
+	for _, repo := range report.Repositories {
+		for _, branch := range repo.Branches {
+			count += len(branch.Commits)
+		}
+	}
+	return count
+}
+
+func (r *Reporter) writeXLSXMetadata(wb *xlsxWorkbook, report *models.Report) error {
+	if err := wb.writeRow(xlsxMetadataSheet, 1, "Setting", "Value"); err != nil {
+		return err
+	}
+	if err := wb.file.SetCellStyle(xlsxMetadataSheet, "A1", "B1", wb.headerStyle); err != nil {
+		return err
+	}
+
+	rows := [][]any{
+		{"Target", report.Target},
+		{"Period start", report.Period.Since},
+		{"Period end", report.Period.Until},
+		{"Generated at", time.Now()},
+		{"Repositories", len(report.Repositories)},
+		{"Contributors", len(report.Summary)},
+	}
+	if commits := reportCommitCount(report); commits > xlsxCommitLimit {
+		rows = append(rows, []any{"Commits sheet", fmt.Sprintf(
+			"truncated to the first %d of %d commits, use -format commits-csv for all of them", xlsxCommitLimit, commits)})
+	}
+	if len(report.Diagnostics) > 0 {
+		rows = append(rows, []any{"Skipped items", len(report.Diagnostics)})
+	}
+	if report.Incomplete != nil {
+		rows = append(rows,
+			[]any{"Incomplete", report.Incomplete.Reason},
+			[]any{"Skipped repositories", strings.Join(report.Incomplete.SkippedRepositories, ", ")})
+	}
+	for _, setting := range r.reportSettings() {
+		rows = append(rows, []any{setting[0], setting[1]})
+	}
+
+	for i, row := range rows {
+		if err := wb.writeRow(xlsxMetadataSheet, i+2, row...); err != nil {
+			return err
+		}
+	}
+	return wb.file.SetColWidth(xlsxMetadataSheet, "A", "B", 28)
+}
+
+// reportSettings describes the options that shaped the report
+func (r *Reporter) reportSettings() [][2]string {
+	branches := "important branches"
+	if r.allBranches {
+		branches = "all branches"
+	}
+	coAuthors := "ignored"
+	if r.coAuthors {
+		coAuthors = "credited (" + string(r.coAuthorSplit) + " split)"
+	}
+
+	return [][2]string{
+		{"Branches", branches},
+		{"Co-authors", coAuthors},
+		{"Attribute by", string(r.attribution)},
+		{"Period by", string(r.periodBasis)},
+		{"Resolve pull requests", strconv.FormatBool(r.resolvePRs || r.prAttribution)},
+		{"Attribute pull requests", strconv.FormatBool(r.prAttribution)},
+		{"Sort order", r.sortOrder},
+	}
+}
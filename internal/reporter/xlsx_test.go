@@ -0,0 +1,108 @@
+package reporter
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestOutputXLSX(t *testing.T) {
+	outputFile := filepath.Join(t.TempDir(), "report.xlsx")
+	r := &Reporter{allBranches: true, attribution: IdentityAuthor, periodBasis: IdentityCommitter}
+
+	if err := r.OutputReport(testReport(), outputFile, "xlsx"); err != nil {
+		t.Fatalf("Failed to write workbook: %v", err)
+	}
+
+	f, err := excelize.OpenFile(outputFile)
+	if err != nil {
+		t.Fatalf("Failed to open workbook: %v", err)
+	}
+	defer f.Close()
+
+	sheets := f.GetSheetList()
+	expectedSheets := []string{xlsxSummarySheet, xlsxMatrixSheet, xlsxCommitsSheet, xlsxMetadataSheet}
+	if len(sheets) != len(expectedSheets) {
+		t.Fatalf("Expected sheets %v, got %v", expectedSheets, sheets)
+	}
+	for i, sheet := range expectedSheets {
+		if sheets[i] != sheet {
+			t.Errorf("Expected sheet %d to be %s, got %s", i, sheet, sheets[i])
+		}
+	}
+
+	tests := []struct {
+		sheet    string
+		cell     string
+		value    string
+		cellType excelize.CellType
+	}{
+		{xlsxSummarySheet, "B2", "johndoe", excelize.CellTypeSharedString},
+		{xlsxSummarySheet, "E2", "10", excelize.CellTypeUnset},
+		{xlsxMatrixSheet, "B1", "owner/repo1", excelize.CellTypeSharedString},
+		{xlsxMatrixSheet, "B2", "1", excelize.CellTypeUnset},
+		{xlsxCommitsSheet, "C2", "abc123", excelize.CellTypeInlineString},
+		{xlsxCommitsSheet, "G2", "2024-01-03 10:00", excelize.CellTypeUnset},
+		{xlsxMetadataSheet, "B2", "owner", excelize.CellTypeSharedString},
+		{xlsxMetadataSheet, "B8", "all branches", excelize.CellTypeSharedString},
+	}
+	for _, tt := range tests {
+		value, err := f.GetCellValue(tt.sheet, tt.cell)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if value != tt.value {
+			t.Errorf("%s!%s: expected %q, got %q", tt.sheet, tt.cell, tt.value, value)
+		}
+
+		// Numbers and dates are stored as numeric cells, which have no explicit type
+		cellType, err := f.GetCellType(tt.sheet, tt.cell)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cellType != tt.cellType {
+			t.Errorf("%s!%s: expected cell type %v, got %v", tt.sheet, tt.cell, tt.cellType, cellType)
+		}
+	}
+}
+
+func TestOutputXLSXTruncatesCommits(t *testing.T) {
+	defer func(limit int) { xlsxCommitLimit = limit }(xlsxCommitLimit)
+	xlsxCommitLimit = 1
+
+	outputFile := filepath.Join(t.TempDir(), "report.xlsx")
+	r := &Reporter{}
+	if err := r.OutputReport(testReport(), outputFile, "xlsx"); err != nil {
+		t.Fatalf("Failed to write workbook: %v", err)
+	}
+
+	f, err := excelize.OpenFile(outputFile)
+	if err != nil {
+		t.Fatalf("Failed to open workbook: %v", err)
+	}
+	defer f.Close()
+
+	rows, err := f.GetRows(xlsxCommitsSheet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 || rows[1][2] != "abc123" {
+		t.Errorf("Expected the header and one commit, got %v", rows)
+	}
+
+	metadata, err := f.GetRows(xlsxMetadataSheet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, row := range metadata {
+		if len(row) == 2 && row[0] == "Commits sheet" && strings.HasPrefix(row[1], "truncated to the first 1 of ") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected a truncation note in the metadata, got %v", metadata)
+	}
+}
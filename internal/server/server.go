@@ -0,0 +1,435 @@
+// Package server exposes reports over HTTP.
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"ghreporting/internal/models"
+	"ghreporting/internal/reporter"
+)
+
+// Job states
+const (
+	StatusQueued  = "queued"
+	StatusRunning = "running"
+	StatusDone    = "done"
+	StatusFailed  = "failed"
+)
+
+// Generator produces a report, e.g. (*reporter.Reporter).GenerateReport
+type Generator func(ctx context.Context, target string, since, until time.Time) (*models.Report, error)
+
+// Options configures a Server
+type Options struct {
+	CacheTTL   time.Duration // How long a generated report is served before it is regenerated
+	MaxJobs    int           // Reports generated at the same time; further jobs are queued
+	MaxQueued  int           // Jobs queued or running at once; further queries are rejected (default 10)
+	MaxReports int           // Generated reports kept in the cache; the oldest are evicted first (default 100)
+	Targets    []string      // Users or organizations that may be queried; empty allows any
+}
+
+// How long failed jobs can be looked up, and how often expired jobs are removed
+const (
+	failedRetention = 10 * time.Minute
+	sweepInterval   = time.Minute
+)
+
+// Errors returned for queries the server refuses to start a job for
+var (
+	errTargetNotAllowed = errors.New("target is not served")
+	errTooManyJobs      = errors.New("too many reports are being generated, try again later")
+)
+
+// Server answers report queries from a cache of generated reports. Reports that are
+// not cached are generated by background jobs so requests never block on the API.
+type Server struct {
+	rep      *reporter.Reporter
+	generate Generator
+	options  Options
+	ctx      context.Context
+	slots    chan struct{}
+
+	mu     sync.Mutex
+	jobs   map[string]*job // By query key
+	byID   map[string]*job
+	nextID int
+	active int // Jobs queued or running
+}
+
+// job generates the report for one target and period
+type job struct {
+	ID       string     `json:"id"`
+	Target   string     `json:"target"`
+	Since    time.Time  `json:"since"`
+	Until    time.Time  `json:"until"`
+	Status   string     `json:"status"`
+	Error    string     `json:"error,omitempty"`
+	Created  time.Time  `json:"created"`
+	Finished *time.Time `json:"finished,omitempty"`
+
+	key    string
+	report *models.Report
+	done   chan struct{}
+}
+
+// New creates a server rendering reports with rep. Jobs run and expired reports are
+// removed until ctx is done.
+func New(ctx context.Context, rep *reporter.Reporter, generate Generator, options Options) *Server {
+	if options.MaxJobs < 1 {
+		options.MaxJobs = 1
+	}
+	if options.MaxQueued < 1 {
+		options.MaxQueued = 10
+	}
+	if options.MaxReports < 1 {
+		options.MaxReports = 100
+	}
+	s := &Server{
+		rep:      rep,
+		generate: generate,
+		options:  options,
+		ctx:      ctx,
+		slots:    make(chan struct{}, options.MaxJobs),
+		jobs:     make(map[string]*job),
+		byID:     make(map[string]*job),
+	}
+	go s.sweepEvery(sweepInterval)
+	return s
+}
+
+// Handler returns the HTTP routes of the server
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /reports", s.handleReport)
+	mux.HandleFunc("GET /contributors/{login}", s.handleContributor)
+	mux.HandleFunc("GET /jobs/{id}", s.handleJob)
+	return mux
+}
+
+// handleReport serves GET /reports?target=&since=&until=&format=&wait=
+func (s *Server) handleReport(w http.ResponseWriter, req *http.Request) {
+	format := req.URL.Query().Get("format")
+	if format == "" {
+		format = "json"
+	}
+	contentType, ok := reporter.ContentType(format)
+	if !ok {
+		httpError(w, http.StatusBadRequest, fmt.Errorf("unsupported format: %s", format))
+		return
+	}
+
+	j, ok := s.lookup(w, req)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	if err := s.rep.WriteReport(w, j.report, format); err != nil {
+		w.Header().Del("Content-Type")
+		httpError(w, http.StatusInternalServerError, err)
+	}
+}
+
+// contributorResponse is one contributor's stats within a report
+type contributorResponse struct {
+	Target string        `json:"target"`
+	Period models.Period `json:"period"`
+	Key    string        `json:"key"`
+	models.ContributorStats
+}
+
+// handleContributor serves GET /contributors/{login}?target=&since=&until=&wait=
+func (s *Server) handleContributor(w http.ResponseWriter, req *http.Request) {
+	login := req.PathValue("login")
+
+	j, ok := s.lookup(w, req)
+	if !ok {
+		return
+	}
+
+	for key, stats := range j.report.Summary {
+		if strings.EqualFold(stats.Login, login) || strings.EqualFold(key, login) {
+			writeJSON(w, http.StatusOK, contributorResponse{
+				Target:           j.report.Target,
+				Period:           j.report.Period,
+				Key:              key,
+				ContributorStats: stats,
+			})
+			return
+		}
+	}
+	httpError(w, http.StatusNotFound, fmt.Errorf("no contributions by %s in this report", login))
+}
+
+// handleJob serves GET /jobs/{id}
+func (s *Server) handleJob(w http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	j, ok := s.byID[req.PathValue("id")]
+	var snapshot job
+	if ok {
+		snapshot = *j
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		httpError(w, http.StatusNotFound, fmt.Errorf("unknown job"))
+		return
+	}
+	writeJSON(w, http.StatusOK, &snapshot)
+}
+
+// lookup returns the finished job for the query, starting one when needed. When the
+// report is not ready it writes 202 Accepted pointing at the job and returns false.
+func (s *Server) lookup(w http.ResponseWriter, req *http.Request) (*job, bool) {
+	query := req.URL.Query()
+	target, since, until, err := parseQuery(query.Get("target"), query.Get("since"), query.Get("until"), time.Now())
+	if err != nil {
+		httpError(w, http.StatusBadRequest, err)
+		return nil, false
+	}
+
+	var wait time.Duration
+	if value := query.Get("wait"); value != "" {
+		if wait, err = time.ParseDuration(value); err != nil {
+			httpError(w, http.StatusBadRequest, fmt.Errorf("invalid wait: %w", err))
+			return nil, false
+		}
+	}
+
+	j, err := s.job(target, since, until)
+	if err != nil {
+		status := http.StatusForbidden
+		if errors.Is(err, errTooManyJobs) {
+			status = http.StatusServiceUnavailable
+			w.Header().Set("Retry-After", "60")
+		}
+		httpError(w, status, err)
+		return nil, false
+	}
+	if wait > 0 {
+		select {
+		case <-j.done:
+		case <-time.After(wait):
+		case <-req.Context().Done():
+		}
+	}
+
+	s.mu.Lock()
+	snapshot := *j
+	s.mu.Unlock()
+
+	switch snapshot.Status {
+	case StatusDone:
+		return &snapshot, true
+	case StatusFailed:
+		httpError(w, http.StatusBadGateway, fmt.Errorf("report generation failed: %s", snapshot.Error))
+	default:
+		w.Header().Set("Location", "/jobs/"+snapshot.ID)
+		w.Header().Set("Retry-After", "10")
+		writeJSON(w, http.StatusAccepted, &snapshot)
+	}
+	return nil, false
+}
+
+// job returns the job for a query, starting a new one when there is none, the cached
+// report has expired or the previous attempt failed. New jobs are refused for targets
+// that are not served and when too many jobs are already queued.
+func (s *Server) job(target string, since, until time.Time) (*job, error) {
+	if !s.allowed(target) {
+		return nil, errTargetNotAllowed
+	}
+	key := strings.ToLower(target) + "|" + since.Format(time.RFC3339) + "|" + until.Format(time.RFC3339)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if j, ok := s.jobs[key]; ok && j.Status != StatusFailed && !s.expired(j, now) {
+		return j, nil
+	}
+	if s.active >= s.options.MaxQueued {
+		return nil, errTooManyJobs
+	}
+	if j, ok := s.jobs[key]; ok {
+		s.remove(j)
+	}
+
+	s.nextID++
+	j := &job{
+		ID:      strconv.Itoa(s.nextID),
+		Target:  target,
+		Since:   since,
+		Until:   until,
+		Status:  StatusQueued,
+		Created: now,
+		key:     key,
+		done:    make(chan struct{}),
+	}
+	s.jobs[key] = j
+	s.byID[j.ID] = j
+	s.active++
+	s.sweep(now)
+	go s.run(j)
+	return j, nil
+}
+
+// allowed reports whether the target may be queried
+func (s *Server) allowed(target string) bool {
+	if len(s.options.Targets) == 0 {
+		return true
+	}
+	for _, allowed := range s.options.Targets {
+		if strings.EqualFold(allowed, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// expired reports whether a finished job's report is older than the cache TTL
+func (s *Server) expired(j *job, now time.Time) bool {
+	return j.Status == StatusDone && s.options.CacheTTL > 0 && now.Sub(*j.Finished) > s.options.CacheTTL
+}
+
+// remove forgets a job; the caller holds s.mu
+func (s *Server) remove(j *job) {
+	if s.jobs[j.key] == j {
+		delete(s.jobs, j.key)
+	}
+	delete(s.byID, j.ID)
+}
+
+// sweep removes expired reports and old failures, then evicts the oldest reports
+// beyond MaxReports; the caller holds s.mu
+func (s *Server) sweep(now time.Time) {
+	var done []*job
+	for _, j := range s.byID {
+		switch {
+		case s.expired(j, now):
+			s.remove(j)
+		case j.Status == StatusFailed && now.Sub(*j.Finished) > failedRetention:
+			s.remove(j)
+		case j.Status == StatusDone:
+			done = append(done, j)
+		}
+	}
+
+	if excess := len(done) - s.options.MaxReports; excess > 0 {
+		sort.Slice(done, func(i, k int) bool { return done[i].Finished.Before(*done[k].Finished) })
+		for _, j := range done[:excess] {
+			s.remove(j)
+		}
+	}
+}
+
+// sweepEvery sweeps the cache at the interval until the server's context is done
+func (s *Server) sweepEvery(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C:
+			s.mu.Lock()
+			s.sweep(now)
+			s.mu.Unlock()
+		case <-s.ctx.Done():
+			return
+		}
+	}
+}
+
+// run generates the job's report once a slot is free
+func (s *Server) run(j *job) {
+	defer close(j.done)
+
+	select {
+	case s.slots <- struct{}{}:
+		defer func() { <-s.slots }()
+	case <-s.ctx.Done():
+		s.finish(j, nil, s.ctx.Err())
+		return
+	}
+
+	s.mu.Lock()
+	j.Status = StatusRunning
+	s.mu.Unlock()
+
+	start := time.Now()
+	slog.Info("Starting report job", "job", j.ID, "target", j.Target)
+	report, err := s.generate(s.ctx, j.Target, j.Since, j.Until)
+	if err == nil && report.Incomplete != nil {
+		err = fmt.Errorf("%s", report.Incomplete.Reason)
+	}
+	s.finish(j, report, err)
+	if err != nil {
+		slog.Error("Report job failed", "job", j.ID, "target", j.Target, "duration", time.Since(start), "error", err)
+		return
+	}
+	slog.Info("Finished report job", "job", j.ID, "target", j.Target, "duration", time.Since(start))
+}
+
+// finish records the outcome of a job
+func (s *Server) finish(j *job, report *models.Report, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	j.Finished = &now
+	s.active--
+	if err != nil {
+		j.Status = StatusFailed
+		j.Error = err.Error()
+		return
+	}
+	j.Status = StatusDone
+	j.report = report
+}
+
+// parseQuery validates the report query. The period defaults to the 30 days before
+// the current hour, so repeated default queries share a cached report.
+func parseQuery(target, since, until string, now time.Time) (string, time.Time, time.Time, error) {
+	if target == "" {
+		return "", time.Time{}, time.Time{}, fmt.Errorf("target parameter is required")
+	}
+
+	untilTime := now.UTC().Truncate(time.Hour)
+	if until != "" {
+		var err error
+		if untilTime, err = time.Parse("2006-01-02", until); err != nil {
+			return "", time.Time{}, time.Time{}, fmt.Errorf("invalid until date: %w", err)
+		}
+	}
+	sinceTime := untilTime.AddDate(0, 0, -30)
+	if since != "" {
+		var err error
+		if sinceTime, err = time.Parse("2006-01-02", since); err != nil {
+			return "", time.Time{}, time.Time{}, fmt.Errorf("invalid since date: %w", err)
+		}
+	}
+	if untilTime.Before(sinceTime) {
+		return "", time.Time{}, time.Time{}, fmt.Errorf("until date is before since date")
+	}
+	return target, sinceTime, untilTime, nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(value); err != nil {
+		slog.Warn("Failed to write response", "error", err)
+	}
+}
+
+func httpError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,282 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"ghreporting/internal/models"
+	"ghreporting/internal/reporter"
+)
+
+func testReport(target string, since, until time.Time) *models.Report {
+	return &models.Report{
+		Target: target,
+		Period: models.Period{Since: since, Until: until},
+		Repositories: []models.Repository{
+			{Name: "repo1", FullName: target + "/repo1"},
+		},
+		Summary: map[string]models.ContributorStats{
+			"johndoe": {
+				Name: "John Doe", Login: "johndoe", TotalCommits: 3, TotalAdditions: 10,
+				Repositories: map[string]models.RepositoryStats{target + "/repo1": {Commits: 3, Additions: 10}},
+			},
+		},
+	}
+}
+
+// newTestServer returns a server whose generator counts its calls and blocks until
+// release is closed
+func newTestServer(t *testing.T, options Options) (*httptest.Server, *atomic.Int32, chan struct{}) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	generate := func(ctx context.Context, target string, since, until time.Time) (*models.Report, error) {
+		calls.Add(1)
+		<-release
+		if target == "broken" {
+			return nil, fmt.Errorf("API unavailable")
+		}
+		return testReport(target, since, until), nil
+	}
+
+	srv := New(context.Background(), reporter.NewReporter(nil), generate, options)
+	ts := httptest.NewServer(srv.Handler())
+	t.Cleanup(ts.Close)
+	return ts, &calls, release
+}
+
+func get(t *testing.T, url string) (*http.Response, string) {
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("Failed to read response: %v", err)
+	}
+	return resp, string(body)
+}
+
+func TestReportJobLifecycle(t *testing.T) {
+	ts, calls, release := newTestServer(t, Options{CacheTTL: time.Hour, MaxJobs: 1})
+	query := ts.URL + "/reports?target=myorg&since=2024-01-01&until=2024-01-31"
+
+	resp, body := get(t, query)
+	if resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("Expected 202 while generating, got %d: %s", resp.StatusCode, body)
+	}
+	location := resp.Header.Get("Location")
+	if location != "/jobs/1" {
+		t.Errorf("Expected Location /jobs/1, got %q", location)
+	}
+
+	// A second request reuses the running job
+	if resp, _ := get(t, query); resp.Header.Get("Location") != location {
+		t.Errorf("Expected the running job to be reused, got %q", resp.Header.Get("Location"))
+	}
+
+	close(release)
+	resp, body = get(t, query+"&wait=5s")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected 200 once generated, got %d: %s", resp.StatusCode, body)
+	}
+	if resp.Header.Get("Content-Type") != "application/json" {
+		t.Errorf("Expected JSON content type, got %q", resp.Header.Get("Content-Type"))
+	}
+	var report models.Report
+	if err := json.Unmarshal([]byte(body), &report); err != nil {
+		t.Fatalf("Failed to decode report: %v", err)
+	}
+	if report.Target != "myorg" || report.Summary["johndoe"].TotalCommits != 3 {
+		t.Errorf("Unexpected report: %+v", report)
+	}
+
+	// Cached reports are rendered in any streamable format without regenerating
+	resp, body = get(t, query+"&format=markdown")
+	if resp.StatusCode != http.StatusOK || !strings.Contains(body, "johndoe") {
+		t.Errorf("Expected markdown report, got %d: %s", resp.StatusCode, body)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("Expected 1 generation, got %d", calls.Load())
+	}
+
+	resp, body = get(t, ts.URL+location)
+	if resp.StatusCode != http.StatusOK || !strings.Contains(body, `"status":"done"`) {
+		t.Errorf("Expected finished job, got %d: %s", resp.StatusCode, body)
+	}
+}
+
+func TestReportCacheExpiry(t *testing.T) {
+	ts, calls, release := newTestServer(t, Options{CacheTTL: time.Millisecond})
+	close(release)
+	query := ts.URL + "/reports?target=myorg&since=2024-01-01&until=2024-01-31&wait=5s"
+
+	if resp, body := get(t, query); resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", resp.StatusCode, body)
+	}
+	time.Sleep(10 * time.Millisecond)
+	if resp, body := get(t, query); resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", resp.StatusCode, body)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("Expected the expired report to be regenerated, got %d generations", calls.Load())
+	}
+}
+
+func TestReportFailedJob(t *testing.T) {
+	ts, calls, release := newTestServer(t, Options{})
+	close(release)
+	query := ts.URL + "/reports?target=broken&wait=5s"
+
+	resp, body := get(t, query)
+	if resp.StatusCode != http.StatusBadGateway || !strings.Contains(body, "API unavailable") {
+		t.Errorf("Expected 502 with the generation error, got %d: %s", resp.StatusCode, body)
+	}
+
+	// Failed jobs are retried by the next request
+	get(t, query)
+	if calls.Load() != 2 {
+		t.Errorf("Expected failed job to be retried, got %d generations", calls.Load())
+	}
+}
+
+func TestContributorEndpoint(t *testing.T) {
+	ts, _, release := newTestServer(t, Options{})
+	close(release)
+
+	resp, body := get(t, ts.URL+"/contributors/JohnDoe?target=myorg&since=2024-01-01&until=2024-01-31&wait=5s")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", resp.StatusCode, body)
+	}
+	var contributor contributorResponse
+	if err := json.Unmarshal([]byte(body), &contributor); err != nil {
+		t.Fatalf("Failed to decode contributor: %v", err)
+	}
+	if contributor.Key != "johndoe" || contributor.TotalAdditions != 10 || contributor.Target != "myorg" {
+		t.Errorf("Unexpected contributor: %+v", contributor)
+	}
+
+	resp, _ = get(t, ts.URL+"/contributors/nobody?target=myorg&since=2024-01-01&until=2024-01-31&wait=5s")
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected 404 for unknown contributor, got %d", resp.StatusCode)
+	}
+}
+
+func TestInvalidQueries(t *testing.T) {
+	ts, calls, _ := newTestServer(t, Options{})
+
+	tests := []string{
+		"/reports",
+		"/reports?target=myorg&since=yesterday",
+		"/reports?target=myorg&since=2024-02-01&until=2024-01-01",
+		"/reports?target=myorg&format=parquet",
+		"/reports?target=myorg&wait=soon",
+	}
+	for _, path := range tests {
+		if resp, body := get(t, ts.URL+path); resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d: %s", path, resp.StatusCode, body)
+		}
+	}
+	if resp, _ := get(t, ts.URL+"/jobs/42"); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected 404 for unknown job, got %d", resp.StatusCode)
+	}
+	if calls.Load() != 0 {
+		t.Errorf("Expected no generation for invalid queries, got %d", calls.Load())
+	}
+}
+
+func TestParseQueryDefaults(t *testing.T) {
+	now := time.Date(2024, 3, 15, 10, 42, 0, 0, time.UTC)
+	_, since, until, err := parseQuery("myorg", "", "", now)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !until.Equal(time.Date(2024, 3, 15, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected until truncated to the hour, got %v", until)
+	}
+	if !since.Equal(until.AddDate(0, 0, -30)) {
+		t.Errorf("Expected since 30 days before until, got %v", since)
+	}
+}
+
+func TestAllowedTargets(t *testing.T) {
+	ts, calls, release := newTestServer(t, Options{Targets: []string{"MyOrg"}})
+	close(release)
+
+	if resp, body := get(t, ts.URL+"/reports?target=other&wait=5s"); resp.StatusCode != http.StatusForbidden {
+		t.Errorf("Expected 403 for a target that is not served, got %d: %s", resp.StatusCode, body)
+	}
+	if resp, body := get(t, ts.URL+"/reports?target=myorg&wait=5s"); resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected targets to match case-insensitively, got %d: %s", resp.StatusCode, body)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("Expected 1 generation, got %d", calls.Load())
+	}
+}
+
+func TestMaxQueuedJobs(t *testing.T) {
+	ts, calls, release := newTestServer(t, Options{MaxJobs: 1, MaxQueued: 2})
+
+	for _, since := range []string{"2024-01-01", "2024-01-02"} {
+		if resp, body := get(t, ts.URL+"/reports?target=myorg&since="+since); resp.StatusCode != http.StatusAccepted {
+			t.Fatalf("Expected 202, got %d: %s", resp.StatusCode, body)
+		}
+	}
+	resp, body := get(t, ts.URL+"/reports?target=myorg&since=2024-01-03")
+	if resp.StatusCode != http.StatusServiceUnavailable || resp.Header.Get("Retry-After") == "" {
+		t.Errorf("Expected 503 with Retry-After beyond the queue limit, got %d: %s", resp.StatusCode, body)
+	}
+
+	// Queued queries are still answered from their job
+	if resp, _ := get(t, ts.URL+"/reports?target=myorg&since=2024-01-01"); resp.StatusCode != http.StatusAccepted {
+		t.Errorf("Expected an existing job to be reused at the limit, got %d", resp.StatusCode)
+	}
+
+	close(release)
+	get(t, ts.URL+"/reports?target=myorg&since=2024-01-02&wait=5s")
+	if resp, body := get(t, ts.URL+"/reports?target=myorg&since=2024-01-03&wait=5s"); resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected new jobs once the queue drained, got %d: %s", resp.StatusCode, body)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("Expected 3 generations, got %d", calls.Load())
+	}
+}
+
+func TestSweep(t *testing.T) {
+	s := New(context.Background(), nil, nil, Options{CacheTTL: time.Hour, MaxReports: 2})
+	now := time.Now()
+	add := func(id, status string, finished time.Time) {
+		j := &job{ID: id, Status: status, Finished: &finished, key: id}
+		s.jobs[id] = j
+		s.byID[id] = j
+	}
+	add("expired", StatusDone, now.Add(-2*time.Hour))
+	add("old-failure", StatusFailed, now.Add(-failedRetention-time.Minute))
+	add("recent-failure", StatusFailed, now.Add(-time.Minute))
+	add("oldest", StatusDone, now.Add(-30*time.Minute))
+	add("older", StatusDone, now.Add(-20*time.Minute))
+	add("newest", StatusDone, now.Add(-10*time.Minute))
+
+	s.sweep(now)
+
+	var kept []string
+	for id := range s.byID {
+		kept = append(kept, id)
+	}
+	sort.Strings(kept)
+	if strings.Join(kept, ",") != "newest,older,recent-failure" {
+		t.Errorf("Unexpected jobs after sweep: %v", kept)
+	}
+	if len(s.jobs) != len(s.byID) {
+		t.Errorf("Expected both indexes to be pruned, got %d and %d", len(s.jobs), len(s.byID))
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+)
+
+// logOutput receives all log output; the progress bar takes it over while it is drawn
+var logOutput = &switchWriter{w: os.Stderr}
+
+// switchWriter is an io.Writer whose destination can be changed while in use
+type switchWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (s *switchWriter) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.w.Write(p)
+}
+
+// SetOutput changes the destination of subsequent writes
+func (s *switchWriter) SetOutput(w io.Writer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.w = w
+}
+
+// logFlags are the logging options shared by every command
+type logFlags struct {
+	level  *string
+	format *string
+}
+
+func addLogFlags(fs *flag.FlagSet) *logFlags {
+	return &logFlags{
+		level:  fs.String("log-level", "info", "Minimum log level: debug, info, warn, error"),
+		format: fs.String("log-format", "text", "Log format: text, json"),
+	}
+}
+
+// setup installs the configured logger as the default for slog and the log package
+func (f *logFlags) setup() error {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(*f.level)); err != nil {
+		return fmt.Errorf("invalid log level: %s (expected debug, info, warn or error)", *f.level)
+	}
+	options := &slog.HandlerOptions{Level: level}
+
+	var handler slog.Handler
+	switch strings.ToLower(*f.format) {
+	case "text":
+		handler = slog.NewTextHandler(logOutput, options)
+	case "json":
+		handler = slog.NewJSONHandler(logOutput, options)
+	default:
+		return fmt.Errorf("invalid log format: %s (expected text or json)", *f.format)
+	}
+
+	slog.SetDefault(slog.New(handler))
+	return nil
+}
+
+// parse parses the command line and installs the configured logger
+func (f *logFlags) parse(fs *flag.FlagSet, args []string) {
+	fs.Parse(args)
+	if err := f.setup(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		fs.Usage()
+		os.Exit(1)
+	}
+}
+
+// fatal logs an error and exits with status 1
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
+}
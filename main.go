@@ -4,29 +4,94 @@ import (
 	"context"
 	"flag"
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"ghreporting/internal/client"
+	"ghreporting/internal/models"
+	"ghreporting/internal/progress"
 	"ghreporting/internal/reporter"
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "render":
+			runRender(os.Args[2:])
+			return
+		case "merge":
+			runMerge(os.Args[2:])
+			return
+		case "serve":
+			runServe(os.Args[2:])
+			return
+		case "daemon":
+			runDaemon(os.Args[2:])
+			return
+		case "generate":
+			runGenerate(os.Args[2:])
+			return
+		}
+	}
+	runGenerate(os.Args[1:])
+}
+
+// runGenerate crawls GitHub and builds a new report
+func runGenerate(args []string) {
+	fs := flag.NewFlagSet("generate", flag.ExitOnError)
 	var (
-		orgUser     = flag.String("target", "", "GitHub organization or user (required)")
-		token       = flag.String("token", "", "GitHub token (optional, can use GITHUB_TOKEN env var)")
-		since       = flag.String("since", "", "Start date (YYYY-MM-DD) for commit analysis (default: 30 days ago)")
-		until       = flag.String("until", "", "End date (YYYY-MM-DD) for commit analysis (default: now)")
-		outputFile  = flag.String("output", "", "Output file path (default: stdout)")
-		format      = flag.String("format", "text", "Output format: text, json, csv")
-		allBranches = flag.Bool("all-branches", false, "Analyze all branches instead of just important ones (main, master, develop, etc.)")
+		orgUser       = fs.String("target", "", "GitHub organization or user (required)")
+		token         = fs.String("token", "", "GitHub token (optional, can use GITHUB_TOKEN env var)")
+		since         = fs.String("since", "", "Start date (YYYY-MM-DD) for commit analysis (default: 30 days ago)")
+		until         = fs.String("until", "", "End date (YYYY-MM-DD) for commit analysis (default: now)")
+		commitsOut    = fs.String("commits-output", "", "Stream every fetched commit to this file (- for stdout)")
+		commitsFmt    = fs.String("commits-format", "csv", "Format of -commits-output: csv, ndjson")
+		allBranches   = fs.Bool("all-branches", false, "Analyze all branches instead of just important ones (main, master, develop, etc.)")
+		resolvePRs    = fs.Bool("resolve-prs", false, "Record the pull request each default branch commit was merged through")
+		githubTeams   = fs.Bool("github-teams", false, "Aggregate contributors per GitHub team of the organization (requires the read:org scope)")
+		repoWorkers   = fs.Int("repo-workers", 10, "Number of repositories processed at once (their API requests are capped by -max-requests)")
+		branchWorkers = fs.Int("branch-workers", 1, "Number of branches of each repository fetched at once (their API requests are capped by -max-requests)")
+		commitWorkers = fs.Int("commit-workers", 1, "Number of commit details fetched at once for each branch (their API requests are capped by -max-requests)")
+		maxRequests   = fs.Int("max-requests", 10, "Number of GitHub API requests in flight at once across all workers; raise it with the worker counts")
+		adaptive      = fs.Bool("adaptive-concurrency", false, "Reduce API requests in flight as the remaining API rate limit shrinks")
+		progressMode  = fs.String("progress", "auto", "Progress display: auto (bar on a terminal, log lines otherwise), bar, log, off")
+		progressEvery = fs.Duration("progress-interval", 30*time.Second, "Interval between progress log lines")
+		previous      = fs.String("previous", "", "Extend this JSON report with commits made since it ended instead of starting from scratch")
+		flags         = addReportFlags(fs)
+		output        = addOutputFlags(fs)
+		deliveries    = addDeliveryFlags(fs)
+		logs          = addLogFlags(fs)
 	)
-	flag.Parse()
+	logs.parse(fs, args)
+
+	// An incremental run continues from the end of the previous report
+	var previousReport *models.Report
+	if *previous != "" {
+		var err error
+		previousReport, err = reporter.LoadReport(*previous)
+		if err != nil {
+			fatal("Failed to load previous report", err)
+		}
+		if previousReport.Incomplete != nil {
+			fatal("Previous report is incomplete, generate it again in full",
+				fmt.Errorf("%d repositories skipped", len(previousReport.Incomplete.SkippedRepositories)))
+		}
+		if *orgUser == "" {
+			*orgUser = previousReport.Target
+		} else if *orgUser != previousReport.Target {
+			fatal("Invalid option", fmt.Errorf("-target %s does not match previous report target %s", *orgUser, previousReport.Target))
+		}
+		if *since == "" {
+			*since = previousReport.Period.Until.Format("2006-01-02")
+		}
+	}
 
 	if *orgUser == "" {
 		fmt.Fprintf(os.Stderr, "Error: -target parameter is required\n")
-		flag.Usage()
+		fs.Usage()
 		os.Exit(1)
 	}
 
@@ -37,43 +102,110 @@ func main() {
 	}
 
 	// Parse dates
-	var sinceTime, untilTime time.Time
-	var err error
+	sinceTime, err := parseDate(*since, time.Now().AddDate(0, 0, -30)) // Default to 30 days ago
+	if err != nil {
+		fatal("Invalid since date", err)
+	}
+	untilTime, err := parseDate(*until, time.Now())
+	if err != nil {
+		fatal("Invalid until date", err)
+	}
 
-	if *since != "" {
-		sinceTime, err = time.Parse("2006-01-02", *since)
-		if err != nil {
-			log.Fatalf("Invalid since date format: %v", err)
-		}
-	} else {
-		sinceTime = time.Now().AddDate(0, 0, -30) // Default to 30 days ago
+	if previousReport != nil && sinceTime.After(previousReport.Period.Until) {
+		fatal("Invalid option", fmt.Errorf("-since %s leaves a gap after the previous report ending %s",
+			sinceTime.Format("2006-01-02"), previousReport.Period.Until.Format("2006-01-02")))
 	}
 
-	if *until != "" {
-		untilTime, err = time.Parse("2006-01-02", *until)
-		if err != nil {
-			log.Fatalf("Invalid until date format: %v", err)
-		}
-	} else {
-		untilTime = time.Now()
+	if err := progress.ValidateMode(*progressMode); err != nil {
+		fatal("Invalid option", err)
 	}
 
 	// Create GitHub client
 	ghClient := client.NewGitHubClient(ghToken)
+	ghClient.SetCommitConcurrency(*commitWorkers, *adaptive)
+	ghClient.SetMaxRequests(*maxRequests)
 
 	// Create reporter
 	rep := reporter.NewReporter(ghClient)
 	rep.SetAllBranches(*allBranches)
+	rep.SetResolvePullRequests(*resolvePRs)
+	rep.SetGitHubTeams(*githubTeams)
+	rep.SetConcurrency(*repoWorkers, *branchWorkers)
+	rep.SetBaseline(previousReport)
+	tracker := progress.NewTracker(ghClient)
+	rep.SetProgress(tracker)
+	if err := flags.configure(rep); err != nil {
+		fatal("Invalid option", err)
+	}
+	if err := deliveries.validate(); err != nil {
+		fatal("Invalid option", err)
+	}
 
-	// Generate report
-	ctx := context.Background()
+	// Validate outputs before spending API requests on the report
+	outputs, err := output.outputs(rep)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	if *commitsOut == "-" {
+		for _, output := range outputs {
+			if output.File == "" || output.File == "-" {
+				fatal("Invalid option", fmt.Errorf("-commits-output - and the %s output cannot both be written to stdout", output.Format))
+			}
+		}
+	}
+
+	var commitWriter *reporter.CommitWriter
+	if *commitsOut != "" {
+		commitWriter, err = reporter.OpenCommitWriter(*commitsOut, *commitsFmt)
+		if err != nil {
+			fatal("Failed to open commit export", err)
+		}
+		rep.SetCommitStream(commitWriter)
+	}
+
+	// Generate report. The first interrupt stops fetching and writes what was finished;
+	// a second one exits immediately.
+	ctx, cancel := context.WithCancelCause(context.Background())
+	defer cancel(nil)
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		sig := <-signals
+		signal.Stop(signals)
+		slog.Warn("Interrupted, finishing in-flight requests and writing a partial report (interrupt again to abort)", "signal", sig)
+		cancel(fmt.Errorf("received %v", sig))
+	}()
+
+	stopProgress := progress.Start(ctx, tracker, *progressMode, os.Stderr, *progressEvery, logOutput.SetOutput)
 	report, err := rep.GenerateReport(ctx, *orgUser, sinceTime, untilTime)
+	stopProgress()
 	if err != nil {
-		log.Fatalf("Error generating report: %v", err)
+		fatal("Failed to generate report", err)
+	}
+
+	if previousReport != nil {
+		report, err = rep.ExtendReport(previousReport, report)
+		if err != nil {
+			fatal("Failed to extend previous report", err)
+		}
+	}
+
+	if commitWriter != nil {
+		if err := commitWriter.Close(); err != nil {
+			fatal("Failed to write commit export", err)
+		}
 	}
 
 	// Output report
-	if err := rep.OutputReport(report, *outputFile, *format); err != nil {
-		log.Fatalf("Error outputting report: %v", err)
+	if err := rep.OutputReports(report, outputs); err != nil {
+		fatal("Failed to output report", err)
 	}
+	if err := deliveries.deliver(context.Background(), rep, report); err != nil {
+		fatal("Failed to deliver report", err)
+	}
+
+	os.Exit(output.exitStatus(report))
 }
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"ghreporting/internal/models"
+	"ghreporting/internal/reporter"
+)
+
+// runMerge combines several saved JSON reports into one
+func runMerge(args []string) {
+	fs := flag.NewFlagSet("merge", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: ghreporting merge [options] report.json [report.json ...]\n")
+		fs.PrintDefaults()
+	}
+	flags := addReportFlags(fs)
+	output := addOutputFlags(fs)
+	deliveries := addDeliveryFlags(fs)
+	logs := addLogFlags(fs)
+	logs.parse(fs, args)
+
+	if fs.NArg() == 0 {
+		fmt.Fprintf(os.Stderr, "Error: at least one JSON report is required\n")
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	rep := reporter.NewReporter(nil)
+	if err := flags.configure(rep); err != nil {
+		fatal("Invalid option", err)
+	}
+	if err := deliveries.validate(); err != nil {
+		fatal("Invalid option", err)
+	}
+
+	outputs, err := output.outputs(rep)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	var reports []*models.Report
+	for _, path := range fs.Args() {
+		report, err := reporter.LoadReport(path)
+		if err != nil {
+			fatal("Failed to load report", err)
+		}
+		reports = append(reports, report)
+	}
+
+	report, err := rep.MergeReports(reports)
+	if err != nil {
+		fatal("Failed to merge reports", err)
+	}
+
+	if err := rep.OutputReports(report, outputs); err != nil {
+		fatal("Failed to output report", err)
+	}
+	if err := deliveries.deliver(context.Background(), rep, report); err != nil {
+		fatal("Failed to deliver report", err)
+	}
+
+	os.Exit(output.exitStatus(report))
+}
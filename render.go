@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"ghreporting/internal/reporter"
+)
+
+// runRender re-renders a saved JSON report without contacting GitHub
+func runRender(args []string) {
+	fs := flag.NewFlagSet("render", flag.ExitOnError)
+	var (
+		input        = fs.String("input", "", "JSON report written by -format json (- for stdin, required)")
+		since        = fs.String("since", "", "Only include commits from this date (YYYY-MM-DD)")
+		until        = fs.String("until", "", "Only include commits up to this date (YYYY-MM-DD)")
+		repos        = fs.String("repos", "", "Comma-separated repository names or glob patterns to include, e.g. myorg/api-*")
+		contributors = fs.String("contributors", "", "Comma-separated contributor logins, emails or names to include")
+		flags        = addReportFlags(fs)
+		output       = addOutputFlags(fs)
+		deliveries   = addDeliveryFlags(fs)
+		logs         = addLogFlags(fs)
+	)
+	logs.parse(fs, args)
+
+	if *input == "" {
+		fmt.Fprintf(os.Stderr, "Error: -input parameter is required\n")
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	var filter reporter.ReportFilter
+	var err error
+	if filter.Since, err = parseDate(*since, time.Time{}); err != nil {
+		fatal("Invalid since date", err)
+	}
+	if filter.Until, err = parseDate(*until, time.Time{}); err != nil {
+		fatal("Invalid until date", err)
+	}
+	filter.Repositories = splitList(*repos)
+	filter.Contributors = splitList(*contributors)
+
+	rep := reporter.NewReporter(nil)
+	if err := flags.configure(rep); err != nil {
+		fatal("Invalid option", err)
+	}
+	if err := deliveries.validate(); err != nil {
+		fatal("Invalid option", err)
+	}
+
+	outputs, err := output.outputs(rep)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	report, err := reporter.LoadReport(*input)
+	if err != nil {
+		fatal("Failed to load report", err)
+	}
+	report, err = rep.FilterReport(report, filter)
+	if err != nil {
+		fatal("Failed to filter report", err)
+	}
+
+	if err := rep.OutputReports(report, outputs); err != nil {
+		fatal("Failed to output report", err)
+	}
+	if err := deliveries.deliver(context.Background(), rep, report); err != nil {
+		fatal("Failed to deliver report", err)
+	}
+
+	os.Exit(output.exitStatus(report))
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"ghreporting/internal/client"
+	"ghreporting/internal/reporter"
+	"ghreporting/internal/server"
+)
+
+// runServe answers report queries over HTTP, generating reports in the background
+func runServe(args []string) {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	var (
+		addr          = fs.String("addr", ":8080", "Address to listen on")
+		targets       = fs.String("targets", "", "Comma-separated users or organizations that may be queried (required)")
+		token         = fs.String("token", "", "GitHub token (optional, can use GITHUB_TOKEN env var)")
+		cacheTTL      = fs.Duration("cache-ttl", time.Hour, "How long a generated report is served before it is regenerated (0 keeps reports forever)")
+		maxJobs       = fs.Int("max-jobs", 2, "Number of reports generated at the same time")
+		maxQueued     = fs.Int("max-queued", 10, "Number of report jobs queued or running before new queries are rejected")
+		maxReports    = fs.Int("max-reports", 100, "Number of generated reports kept in the cache")
+		allBranches   = fs.Bool("all-branches", false, "Analyze all branches instead of just important ones (main, master, develop, etc.)")
+		resolvePRs    = fs.Bool("resolve-prs", false, "Record the pull request each default branch commit was merged through")
+		githubTeams   = fs.Bool("github-teams", false, "Aggregate contributors per GitHub team of the organization (requires the read:org scope)")
+		repoWorkers   = fs.Int("repo-workers", 10, "Number of repositories processed at once (their API requests are capped by -max-requests)")
+		branchWorkers = fs.Int("branch-workers", 1, "Number of branches of each repository fetched at once (their API requests are capped by -max-requests)")
+		commitWorkers = fs.Int("commit-workers", 1, "Number of commit details fetched at once for each branch (their API requests are capped by -max-requests)")
+		maxRequests   = fs.Int("max-requests", 10, "Number of GitHub API requests in flight at once across all workers; raise it with the worker counts")
+		adaptive      = fs.Bool("adaptive-concurrency", false, "Reduce API requests in flight as the remaining API rate limit shrinks")
+		flags         = addReportFlags(fs)
+		logs          = addLogFlags(fs)
+	)
+	logs.parse(fs, args)
+
+	var allowed []string
+	for _, target := range strings.Split(*targets, ",") {
+		if target = strings.TrimSpace(target); target != "" {
+			allowed = append(allowed, target)
+		}
+	}
+	if len(allowed) == 0 {
+		fmt.Fprintf(os.Stderr, "Error: -targets parameter is required\n")
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	ghToken := *token
+	if ghToken == "" {
+		ghToken = os.Getenv("GITHUB_TOKEN")
+	}
+
+	ghClient := client.NewGitHubClient(ghToken)
+	ghClient.SetCommitConcurrency(*commitWorkers, *adaptive)
+	ghClient.SetMaxRequests(*maxRequests)
+
+	rep := reporter.NewReporter(ghClient)
+	rep.SetAllBranches(*allBranches)
+	rep.SetResolvePullRequests(*resolvePRs)
+	rep.SetGitHubTeams(*githubTeams)
+	rep.SetConcurrency(*repoWorkers, *branchWorkers)
+	if err := flags.configure(rep); err != nil {
+		fatal("Invalid option", err)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	srv := server.New(ctx, rep, rep.GenerateReport, server.Options{
+		CacheTTL:   *cacheTTL,
+		MaxJobs:    *maxJobs,
+		MaxQueued:  *maxQueued,
+		MaxReports: *maxReports,
+		Targets:    allowed,
+	})
+	httpServer := &http.Server{
+		Addr:              *addr,
+		Handler:           srv.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		slog.Info("Listening", "addr", *addr)
+		errs <- httpServer.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errs:
+		fatal("Failed to serve", err)
+	case <-ctx.Done():
+	}
+
+	slog.Info("Shutting down")
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancelShutdown()
+	if err := httpServer.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		fatal("Failed to shut down", err)
+	}
+}